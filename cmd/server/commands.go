@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/database"
+	"github.com/julietteengel/fizzbuzz-api/internal/repository"
+	"github.com/julietteengel/fizzbuzz-api/internal/service"
+)
+
+// runCommand runs a subcommand of the server binary instead of the server
+func runCommand(name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrate(args)
+	case "stats":
+		return runStats(args)
+	default:
+		return errors.New("unknown command: expected migrate or stats, or no argument to start the server")
+	}
+}
+
+const migrateUsage = "usage: fizzbuzz-api migrate up | down [steps] | status"
+
+// runMigrate applies, reverts or lists the schema migrations of the configured database:
+//
+//	fizzbuzz-api migrate up          apply every pending migration
+//	fizzbuzz-api migrate down [n]    revert the last n migrations (default 1)
+//	fizzbuzz-api migrate status      list migrations and when they were applied
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Database.StatsStorage == "memory" {
+		return fmt.Errorf("STATS_STORAGE is memory: set it to postgres or sqlite to migrate a database")
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		return database.MigrateUp(db)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q: expected a positive number", args[1])
+			}
+		}
+		return database.MigrateDown(db, steps)
+
+	case "status":
+		statuses, err := database.GetMigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.Unknown {
+				state += " (unknown to this binary)"
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+const statsUsage = "usage: fizzbuzz-api stats export [-format json|csv] [-output file] | import [-format json|csv] file"
+
+// runStats exports or imports the stats of the configured storage, like the /api/v1/admin/stats endpoints:
+//
+//	fizzbuzz-api stats export [-format json|csv] [-output file]    write every entry, to stdout by default
+//	fizzbuzz-api stats import [-format json|csv] file              add the entries of file, "-" for stdin
+//
+// The format defaults to the extension of the file, then json. In memory mode the stats live in the
+// server process: the commands only work on the snapshot file, and refuse to while the server runs.
+func runStats(args []string) error {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		return errors.New(statsUsage)
+	}
+
+	flags := flag.NewFlagSet("stats "+args[0], flag.ContinueOnError)
+	format := flags.String("format", "", "json or csv (default: from the file extension, then json)")
+	output := flags.String("output", "", "file to write the export to (default: stdout)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Database.StatsStorage == "memory" && cfg.Stats.MemorySnapshotPath == "" {
+		return errors.New("STATS_STORAGE is memory without STATS_MEMORY_SNAPSHOT_PATH: the stats only live in the server, use the /api/v1/admin/stats endpoints")
+	}
+
+	db, err := database.NewGormDB(cfg)
+	if err != nil {
+		return err
+	}
+	// In memory mode, fails while the server holds the snapshot
+	statsRepo, err := repository.NewStatsRepository(db, cfg)
+	if err != nil {
+		return err
+	}
+	statsService := service.NewStatsService(statsRepo, cfg)
+
+	if args[0] == "export" {
+		err = exportStats(statsService, *format, *output)
+	} else {
+		err = importStats(statsService, *format, flags.Args())
+	}
+
+	// Close writes buffered hits, and the snapshot in memory mode
+	return errors.Join(err, statsRepo.Close())
+}
+
+func exportStats(statsService service.IStatsService, format, output string) error {
+	format = statsFileFormat(format, output)
+
+	export, err := statsService.ExportStats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := service.EncodeStatsExport(w, format, export); err != nil {
+		return err
+	}
+	log.Printf("Exported %d stats entries", len(export.Entries))
+	return nil
+}
+
+func importStats(statsService service.IStatsService, format string, args []string) error {
+	if len(args) != 1 {
+		return errors.New(statsUsage)
+	}
+	format = statsFileFormat(format, args[0])
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	entries, err := service.DecodeStatsImport(r, format)
+	if err != nil {
+		return err
+	}
+	result, err := statsService.ImportStats(context.Background(), entries)
+	if err != nil {
+		return err
+	}
+	log.Printf("Imported %d stats entries, %d hits", result.Entries, result.Hits)
+	return nil
+}
+
+// statsFileFormat returns format when set, else the format matching the extension of path
+func statsFileFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	if filepath.Ext(path) == ".csv" {
+		return service.StatsTransferCSV
+	}
+	return service.StatsTransferJSON
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/fx"
 
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
 	"github.com/julietteengel/fizzbuzz-api/internal/config"
 	"github.com/julietteengel/fizzbuzz-api/internal/controller"
 	"github.com/julietteengel/fizzbuzz-api/internal/database"
@@ -27,25 +30,43 @@ import (
 // @host localhost:8080
 // @BasePath /api/v1
 // @schemes http https
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin endpoints are enabled by setting ADMIN_TOKEN. Send "Bearer <ADMIN_TOKEN>".
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	fx.New( //creates and initializes an App
 		fx.Provide( // registers any number of constructor functions, teaching the application how to instantiate various types.
 			config.Load,
 			database.NewGormDB,
 			repository.NewStatsRepository,
+			service.NewStatsRecorder,
 			service.NewFizzBuzzService,
 			service.NewStatsService,
 			controller.NewFizzBuzzController,
 			controller.NewStatsController,
 			newEcho,
 		),
-		fx.Invoke(setupRoutes), //Invoke registers functions that are executed eagerly on application start.
+		// OnStop hooks run in reverse order: the server stops first, then pending stats are written, then the repository closes
+		fx.Invoke(closeStatsRepository, drainStatsRecorder, setupRoutes), //Invoke registers functions that are executed eagerly on application start.
 	).Run()
 }
 
-func newEcho() *echo.Echo {
+// maxImportBodySize caps the documents accepted by POST /admin/stats/import
+const maxImportBodySize = "64M"
+
+func newEcho(cfg *config.Config) *echo.Echo {
 	e := echo.New()
+	e.HTTPErrorHandler = errors.NewHTTPErrorHandler(cfg.App.ErrorFormat == config.ErrorFormatProblem) // Every error as a model.ErrorResponse or problem details
+	e.Validator = controller.NewRequestValidator(cfg)                                                 // Checks the validate tags of request structs
 
 	//1. Middleware Stack:
 	e.Use(middleware.Logger())    // Logs every request
@@ -67,7 +88,22 @@ func setupRoutes(
 	//2. Route Grouping:
 	api := e.Group("/api/v1") // Prefix all API routes
 	api.POST("/fizzbuzz", fizzBuzzController.GenerateFizzBuzz)
+	api.GET("/fizzbuzz", fizzBuzzController.GetFizzBuzz)
+	api.POST("/fizzbuzz/batch", fizzBuzzController.GenerateFizzBuzzBatch)
+	api.POST("/fizzbuzz/stream", fizzBuzzController.StreamFizzBuzz)
 	api.GET("/stats", statsController.GetStats)
+	api.GET("/stats/top", statsController.GetTopStats)
+	api.GET("/stats/store", statsController.GetStoreMetrics)
+
+	// Admin routes, only served when a token is configured
+	if cfg.App.AdminToken != "" {
+		adminAuth := controller.AdminAuth(cfg.App.AdminToken)
+		admin := api.Group("/admin", adminAuth)
+		admin.GET("/stats/export", statsController.ExportStats)
+		admin.POST("/stats/import", statsController.ImportStats, middleware.BodyLimit(maxImportBodySize))
+		api.DELETE("/stats", statsController.DeleteStats, adminAuth)
+		api.DELETE("/stats/:id", statsController.DeleteStatsEntry, adminAuth)
+	}
 
 	// Health check (outside API group)
 	e.GET("/health", fizzBuzzController.HealthCheck)
@@ -90,3 +126,21 @@ func setupRoutes(
 		},
 	})
 }
+
+// closeStatsRepository stops the repository background work when the application stops
+func closeStatsRepository(lc fx.Lifecycle, statsRepo repository.IStatsRepository) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return statsRepo.Close()
+		},
+	})
+}
+
+// drainStatsRecorder writes the stats still queued once the server no longer accepts requests
+func drainStatsRecorder(lc fx.Lifecycle, statsRecorder service.IStatsRecorder) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return statsRecorder.Close(ctx)
+		},
+	})
+}
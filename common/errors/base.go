@@ -1,38 +1,186 @@
 package errors
 
 import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
-)
 
-// Translation represents error messages in multiple languages
-type Translation struct {
-	Fr string `json:"fr"`
-	En string `json:"en"`
-}
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
 
-// ControllerError represents a standardized error structure
+// ControllerError represents a standardized error structure. Its messages are in the catalog, under its name.
 type ControllerError struct {
-	Name          string      `json:"name"`
-	HttpErrorCode int         `json:"code"`
-	Translation   Translation `json:"translation"`
+	Name          string                 `json:"name"`
+	HttpErrorCode int                    `json:"code"`
+	Params        map[string]interface{} `json:"-"` // Values of the message templates, e.g. {"Max": 10000}
 }
 
 func (e ControllerError) Error() string {
-	return e.Translation.En
+	return e.Message(DefaultLanguage)
+}
+
+// With returns a copy of the error whose messages are rendered with params
+func (e ControllerError) With(params map[string]interface{}) ControllerError {
+	e.Params = params
+	return e
+}
+
+// wrappedError is the internal error of the *echo.HTTPError returned by WrapErrorHTTP: it carries
+// what HTTPErrorHandler needs to render the body besides the translated message
+type wrappedError struct {
+	ControllerError
+	details     map[string]string
+	fieldErrors []model.ValidationError
 }
 
 // WrapErrorHTTP logs the original error and returns a properly formatted HTTP error
 func WrapErrorHTTP(c echo.Context, originalErr error, controllerError ControllerError) error {
+	return wrapErrorHTTP(c, originalErr, &wrappedError{ControllerError: controllerError})
+}
+
+// WrapErrorHTTPWithDetails is WrapErrorHTTP with details added to the error body
+func WrapErrorHTTPWithDetails(c echo.Context, originalErr error, controllerError ControllerError, details map[string]string) error {
+	return wrapErrorHTTP(c, originalErr, &wrappedError{ControllerError: controllerError, details: details})
+}
+
+// WrapValidationErrorHTTP returns controllerError with the invalid fields listed in the error body
+func WrapValidationErrorHTTP(c echo.Context, controllerError ControllerError, fieldErrors []model.ValidationError) error {
+	return wrapErrorHTTP(c, nil, &wrappedError{ControllerError: controllerError, fieldErrors: fieldErrors})
+}
+
+func wrapErrorHTTP(c echo.Context, originalErr error, wrapped *wrappedError) error {
 	if originalErr != nil {
-		log.Errorf("Error %s: %v", controllerError.Name, originalErr)
+		log.Errorf("Error %s: %v", wrapped.Name, originalErr)
 	}
 
-	lang := c.Request().Header.Get("Accept-Language")
-	message := controllerError.Translation.En
-	if lang == "fr" || lang == "fr-FR" {
-		message = controllerError.Translation.Fr
+	return echo.NewHTTPError(wrapped.HttpErrorCode, Translate(c, wrapped.ControllerError)).SetInternal(wrapped)
+}
+
+// Translate returns the error message in the language requested by the client, see Language
+func Translate(c echo.Context, controllerError ControllerError) string {
+	return controllerError.Message(Language(c))
+}
+
+// MIMEApplicationProblemJSON is the media type of RFC 7807 problem details
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemTypePrefix prefixes the name of a ControllerError to form the type URI of its problem details
+const problemTypePrefix = "urn:fizzbuzz-api:error:"
+
+// HTTPErrorHandler renders every error as a model.ErrorResponse, or as RFC 7807 problem details
+// when the client accepts application/problem+json. Errors returned by WrapErrorHTTP keep their code
+// and details; errors raised by Echo itself (unknown route, body too large...) get the ControllerError
+// of their status, and any other error is an internal ServiceError.
+func HTTPErrorHandler(err error, c echo.Context) {
+	handleHTTPError(err, c, false)
+}
+
+// NewHTTPErrorHandler returns HTTPErrorHandler, rendering problem details to every client when problemDetails is set
+func NewHTTPErrorHandler(problemDetails bool) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		handleHTTPError(err, c, problemDetails)
 	}
+}
+
+func handleHTTPError(err error, c echo.Context, problemDetails bool) {
+	if c.Response().Committed {
+		return
+	}
+
+	code, response := errorResponse(err, c)
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(code)
+	} else if problemDetails || acceptsProblemJSON(c.Request().Header.Get(echo.HeaderAccept)) {
+		err = writeProblemDetails(c, code, response)
+	} else {
+		err = c.JSON(code, response)
+	}
+	if err != nil {
+		log.Errorf("Failed to write error response: %v", err)
+	}
+}
+
+// errorResponse maps err to its status code and body
+func errorResponse(err error, c echo.Context) (int, model.ErrorResponse) {
+	var wrapped *wrappedError
+	var response model.ErrorResponse
+	code := http.StatusInternalServerError
+
+	var he *echo.HTTPError
+	switch {
+	case stderrors.As(err, &he) && stderrors.As(he.Internal, &wrapped):
+		code = he.Code
+		response.Error = wrapped.Name
+		response.Message, _ = he.Message.(string)
+		response.Details = wrapped.details
+		response.Errors = wrapped.fieldErrors
+
+	case he != nil:
+		code = he.Code
+		controllerError, known := httpStatusErrors[code]
+		if !known {
+			controllerError = InvalidRequestError
+			if code >= http.StatusInternalServerError {
+				controllerError = ServiceError
+			}
+		}
+		response.Error = controllerError.Name
+		response.Message = Translate(c, controllerError)
 
-	return echo.NewHTTPError(controllerError.HttpErrorCode, message)
-}
\ No newline at end of file
+	default:
+		log.Errorf("Error %s: %v", ServiceError.Name, err)
+		response.Error = ServiceError.Name
+		response.Message = Translate(c, ServiceError)
+	}
+
+	response.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	if response.RequestID == "" {
+		response.RequestID = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+	return code, response
+}
+
+// writeProblemDetails renders response as RFC 7807 problem details: the error code becomes the problem
+// type, the translated message its detail, and the other fields are kept as extension members
+func writeProblemDetails(c echo.Context, code int, response model.ErrorResponse) error {
+	body, err := json.Marshal(model.ProblemDetails{
+		Type:      problemTypePrefix + response.Error,
+		Title:     http.StatusText(code),
+		Status:    code,
+		Detail:    response.Message,
+		Instance:  c.Request().URL.RequestURI(),
+		Code:      response.Error,
+		RequestID: response.RequestID,
+		Details:   response.Details,
+		Errors:    response.Errors,
+	})
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, MIMEApplicationProblemJSON, body)
+}
+
+// acceptsProblemJSON reports whether the Accept header lists application/problem+json with a non-zero quality
+func acceptsProblemJSON(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), MIMEApplicationProblemJSON) {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if name, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && name == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
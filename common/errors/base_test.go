@@ -0,0 +1,194 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		acceptLanguage string
+		expectedCode   int
+		expected       model.ErrorResponse
+	}{
+		{
+			name:         "wrapped",
+			path:         "/wrapped",
+			expectedCode: http.StatusBadRequest,
+			expected:     model.ErrorResponse{Error: "ValidationCursorError", Message: ValidationCursorError.Message("en")},
+		},
+		{
+			name:           "wrapped_translated",
+			path:           "/wrapped",
+			acceptLanguage: "fr",
+			expectedCode:   http.StatusBadRequest,
+			expected:       model.ErrorResponse{Error: "ValidationCursorError", Message: ValidationCursorError.Message("fr")},
+		},
+		{
+			name:           "wrapped_negotiated",
+			path:           "/wrapped",
+			acceptLanguage: "fr-CA;q=0.8, es;q=0.9",
+			expectedCode:   http.StatusBadRequest,
+			expected:       model.ErrorResponse{Error: "ValidationCursorError", Message: ValidationCursorError.Message("es")},
+		},
+		{
+			name:         "details",
+			path:         "/details",
+			expectedCode: http.StatusBadRequest,
+			expected: model.ErrorResponse{
+				Error:   "InvalidRequestError",
+				Message: InvalidRequestError.Message("en"),
+				Details: map[string]string{"cause": "unexpected EOF"},
+			},
+		},
+		{
+			name:         "field_errors",
+			path:         "/fields",
+			expectedCode: http.StatusBadRequest,
+			expected: model.ErrorResponse{
+				Error:   "ValidationStatsImportError",
+				Message: ValidationStatsImportError.Message("en"),
+				Errors:  []model.ValidationError{{Field: "entry 2", Message: "limit must be 1 or more"}},
+			},
+		},
+		{
+			name:         "unknown_route",
+			path:         "/missing",
+			expectedCode: http.StatusNotFound,
+			expected:     model.ErrorResponse{Error: "RouteNotFoundError", Message: RouteNotFoundError.Message("en")},
+		},
+		{
+			name:         "method_not_allowed",
+			method:       http.MethodPost,
+			path:         "/wrapped",
+			expectedCode: http.StatusMethodNotAllowed,
+			expected:     model.ErrorResponse{Error: "MethodNotAllowedError", Message: MethodNotAllowedError.Message("en")},
+		},
+		{
+			name:         "plain_error",
+			path:         "/plain",
+			expectedCode: http.StatusInternalServerError,
+			expected:     model.ErrorResponse{Error: "ServiceError", Message: ServiceError.Message("en")},
+		},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(middleware.RequestID())
+	e.GET("/wrapped", func(c echo.Context) error {
+		return WrapErrorHTTP(c, nil, ValidationCursorError)
+	})
+	e.GET("/details", func(c echo.Context) error {
+		return WrapErrorHTTPWithDetails(c, nil, InvalidRequestError, map[string]string{"cause": "unexpected EOF"})
+	})
+	e.GET("/fields", func(c echo.Context) error {
+		return WrapValidationErrorHTTP(c, ValidationStatsImportError, []model.ValidationError{{Field: "entry 2", Message: "limit must be 1 or more"}})
+	})
+	e.GET("/plain", func(c echo.Context) error {
+		return fmt.Errorf("connection refused")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.NotEmpty(t, response.RequestID)
+			assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), response.RequestID)
+
+			response.RequestID = ""
+			assert.Equal(t, tt.expected, response)
+		})
+	}
+}
+
+func TestHTTPErrorHandler_ClientRequestID(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(middleware.RequestID())
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set(echo.HeaderXRequestID, "trace-42")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var response model.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "trace-42", response.RequestID)
+}
+
+func TestHTTPErrorHandler_ProblemDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		problemDetails bool
+		accept         string
+		expectProblem  bool
+	}{
+		{name: "default_json", accept: echo.MIMEApplicationJSON},
+		{name: "accept_problem", accept: "application/problem+json", expectProblem: true},
+		{name: "accept_problem_among_others", accept: "application/json;q=0.9, application/problem+json", expectProblem: true},
+		{name: "accept_problem_refused", accept: "application/problem+json;q=0, application/json"},
+		{name: "configured", problemDetails: true, accept: echo.MIMEApplicationJSON, expectProblem: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = NewHTTPErrorHandler(tt.problemDetails)
+			e.Use(middleware.RequestID())
+			e.GET("/fields", func(c echo.Context) error {
+				return WrapValidationErrorHTTP(c, ValidationStatsImportError, []model.ValidationError{{Field: "entry 2", Message: "limit must be 1 or more"}})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/fields?format=csv", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			if !tt.expectProblem {
+				assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+				return
+			}
+
+			assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+			var problem model.ProblemDetails
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.NotEmpty(t, problem.RequestID)
+			problem.RequestID = ""
+			assert.Equal(t, model.ProblemDetails{
+				Type:     "urn:fizzbuzz-api:error:ValidationStatsImportError",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   ValidationStatsImportError.Message("en"),
+				Instance: "/fields?format=csv",
+				Code:     "ValidationStatsImportError",
+				Errors:   []model.ValidationError{{Field: "entry 2", Message: "limit must be 1 or more"}},
+			}, problem)
+		})
+	}
+}
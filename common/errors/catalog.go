@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultLanguage is used when Accept-Language matches no language of the catalog, and for messages missing from a language
+const DefaultLanguage = "en"
+
+//go:embed translations/*.json
+var translationFiles embed.FS
+
+// catalog holds the messages of each language by ControllerError name. A language is added by adding
+// translations/<tag>.json, e.g. translations/pt-BR.json: nothing else has to change.
+// Messages are text/template templates, rendered with ControllerError.Params, e.g. "between 1 and {{.Max}}".
+var catalog, languages = mustLoadCatalog(translationFiles)
+
+// loadCatalog reads the translations/*.json files of files, named after their language tag
+func loadCatalog(files fs.FS) (map[string]map[string]*template.Template, []string, error) {
+	paths, err := fs.Glob(files, "translations/*.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded := make(map[string]map[string]*template.Template, len(paths))
+	tags := make([]string, 0, len(paths))
+	for _, file := range paths {
+		content, err := fs.ReadFile(files, file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(content, &messages); err != nil {
+			return nil, nil, fmt.Errorf("invalid translation file %s: %w", file, err)
+		}
+
+		tag := strings.TrimSuffix(path.Base(file), ".json")
+		loaded[tag] = make(map[string]*template.Template, len(messages))
+		for name, message := range messages {
+			// A parameter missing from ControllerError.Params is an error rather than "<no value>" in the message
+			tmpl, err := template.New(name).Option("missingkey=error").Parse(message)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid translation file %s: %w", file, err)
+			}
+			loaded[tag][name] = tmpl
+		}
+		tags = append(tags, tag)
+	}
+
+	if _, ok := loaded[DefaultLanguage]; !ok {
+		return nil, nil, fmt.Errorf("missing translation file for the default language %s", DefaultLanguage)
+	}
+
+	sort.Strings(tags)
+	return loaded, tags, nil
+}
+
+// mustLoadCatalog loads the embedded catalog: the files are part of the binary, so an error is a build error
+func mustLoadCatalog(files fs.FS) (map[string]map[string]*template.Template, []string) {
+	loaded, tags, err := loadCatalog(files)
+	if err != nil {
+		panic(err)
+	}
+	return loaded, tags
+}
+
+// Message returns the message of the error in lang, a language of the catalog, rendered with e.Params.
+// It falls back to DefaultLanguage when lang has no message for the error, and to the error name
+// when no message can be rendered.
+func (e ControllerError) Message(lang string) string {
+	for _, tag := range []string{lang, DefaultLanguage} {
+		tmpl, ok := catalog[tag][e.Name]
+		if !ok {
+			continue
+		}
+
+		var message strings.Builder
+		if err := tmpl.Execute(&message, e.Params); err == nil {
+			return message.String()
+		}
+	}
+	return e.Name
+}
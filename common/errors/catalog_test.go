@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog_LanguagesHaveEveryMessage(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr"}, languages)
+
+	expected := make([]string, 0, len(catalog[DefaultLanguage]))
+	for name := range catalog[DefaultLanguage] {
+		expected = append(expected, name)
+	}
+	sort.Strings(expected)
+
+	for _, language := range languages {
+		names := make([]string, 0, len(catalog[language]))
+		for name, message := range catalog[language] {
+			assert.NotEmpty(t, message, "%s: %s", language, name)
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		assert.Equal(t, expected, names, language)
+	}
+}
+
+func TestControllerError_Message(t *testing.T) {
+	assert.Equal(t, "Parameter rules must contain at most 10 rules.", ValidationRulesError.Message("en"))
+	assert.Equal(t, "Le paramètre rules doit contenir au plus 10 règles.", ValidationRulesError.Message("fr"))
+	assert.Equal(t, ValidationRulesError.Message("en"), ValidationRulesError.Message("ja"))
+	assert.Equal(t, ValidationRulesError.Message("en"), ValidationRulesError.Error())
+	assert.Equal(t, "UnknownError", ControllerError{Name: "UnknownError"}.Message("fr"))
+}
+
+func TestControllerError_Message_Params(t *testing.T) {
+	bounded := ValidationInt1Error.With(map[string]interface{}{"Min": 2, "Max": 50})
+	assert.Equal(t, "Parameter int1 must be between 2 and 50.", bounded.Message("en"))
+	assert.Equal(t, "Le paramètre int1 doit être entre 2 et 50.", bounded.Message("fr"))
+	assert.Equal(t, "Der Parameter int1 muss zwischen 2 und 50 liegen.", bounded.Message("de"))
+	assert.Equal(t, "El parámetro int1 debe ser entre 2 y 50.", bounded.Message("es"))
+
+	unbounded := ValidationInt1Error.With(map[string]interface{}{"Min": 1, "Max": 0})
+	assert.Equal(t, "Parameter int1 must be 1 or more.", unbounded.Message("en"))
+	assert.Equal(t, "Le paramètre int1 doit être supérieur ou égal à 1.", unbounded.Message("fr"))
+
+	limit := ValidationLimitError.With(map[string]interface{}{"Max": 500})
+	assert.Equal(t, "Parameter limit must be between 1 and 500.", limit.Message("en"))
+	assert.Equal(t, "Der Parameter limit muss zwischen 1 und 500 liegen.", limit.Message("de"))
+
+	// Without its params, a message cannot be rendered
+	assert.Equal(t, "ValidationLimitError", ValidationLimitError.Message("en"))
+	assert.Empty(t, ValidationInt1Error.Params, "With must not modify the error it is called on")
+}
+
+func TestLoadCatalog(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		loaded, tags, err := loadCatalog(fstest.MapFS{
+			"translations/en.json":    {Data: []byte(`{"ServiceError": "Failed."}`)},
+			"translations/pt-BR.json": {Data: []byte(`{"ServiceError": "Falhou."}`)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"en", "pt-BR"}, tags)
+		var message strings.Builder
+		assert.NoError(t, loaded["pt-BR"]["ServiceError"].Execute(&message, nil))
+		assert.Equal(t, "Falhou.", message.String())
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		_, _, err := loadCatalog(fstest.MapFS{
+			"translations/en.json": {Data: []byte(`{"ServiceError": 1}`)},
+		})
+		assert.ErrorContains(t, err, "translations/en.json")
+	})
+
+	t.Run("invalid_template", func(t *testing.T) {
+		_, _, err := loadCatalog(fstest.MapFS{
+			"translations/en.json": {Data: []byte(`{"ValidationLimitError": "Between 1 and {{.Max."}`)},
+		})
+		assert.ErrorContains(t, err, "translations/en.json")
+	})
+
+	t.Run("missing_default_language", func(t *testing.T) {
+		_, _, err := loadCatalog(fstest.MapFS{
+			"translations/fr.json": {Data: []byte(`{"ServiceError": "Échec."}`)},
+		})
+		assert.ErrorContains(t, err, "default language")
+	})
+}
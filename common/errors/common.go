@@ -7,18 +7,43 @@ var (
 	InvalidRequestError = ControllerError{
 		Name:          "InvalidRequestError",
 		HttpErrorCode: http.StatusBadRequest,
-		Translation: Translation{
-			Fr: "Impossible de parser le corps de la requête.",
-			En: "Failed to parse request body.",
-		},
+	}
+
+	NotAcceptableError = ControllerError{
+		Name:          "NotAcceptableError",
+		HttpErrorCode: http.StatusNotAcceptable,
+	}
+
+	UnauthorizedError = ControllerError{
+		Name:          "UnauthorizedError",
+		HttpErrorCode: http.StatusUnauthorized,
+	}
+
+	RouteNotFoundError = ControllerError{
+		Name:          "RouteNotFoundError",
+		HttpErrorCode: http.StatusNotFound,
+	}
+
+	MethodNotAllowedError = ControllerError{
+		Name:          "MethodNotAllowedError",
+		HttpErrorCode: http.StatusMethodNotAllowed,
+	}
+
+	RequestTooLargeError = ControllerError{
+		Name:          "RequestTooLargeError",
+		HttpErrorCode: http.StatusRequestEntityTooLarge,
 	}
 
 	ServiceError = ControllerError{
 		Name:          "ServiceError",
 		HttpErrorCode: http.StatusInternalServerError,
-		Translation: Translation{
-			Fr: "Erreur lors du traitement de la requête.",
-			En: "Failed to process request.",
-		},
 	}
 )
+
+// httpStatusErrors names the errors Echo raises by itself, which HTTPErrorHandler renders like the others
+var httpStatusErrors = map[int]ControllerError{
+	http.StatusUnauthorized:          UnauthorizedError,
+	http.StatusNotFound:              RouteNotFoundError,
+	http.StatusMethodNotAllowed:      MethodNotAllowedError,
+	http.StatusRequestEntityTooLarge: RequestTooLargeError,
+}
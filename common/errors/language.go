@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Language returns the language of the catalog that best matches the Accept-Language header of the request
+func Language(c echo.Context) string {
+	return LookupLanguage(c.Request().Header.Get("Accept-Language"), languages, DefaultLanguage)
+}
+
+// LookupLanguage implements the lookup scheme of RFC 4647 (section 3.4) on an Accept-Language header:
+// language ranges are tried by decreasing quality, each one being shortened a subtag at a time
+// (fr-CA, then fr) until it equals a tag of available, compared without case.
+// Ranges with q=0 and the "*" wildcard are skipped; defaultLanguage is returned when nothing matches.
+func LookupLanguage(acceptLanguage string, available []string, defaultLanguage string) string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				} else {
+					quality = 0
+				}
+			}
+		}
+
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+
+	// Ranges of equal quality keep the order of the header
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, languageRange := range ranges {
+		for tag := languageRange.tag; tag != ""; tag = truncateLanguageTag(tag) {
+			for _, language := range available {
+				if strings.EqualFold(language, tag) {
+					return language
+				}
+			}
+		}
+	}
+	return defaultLanguage
+}
+
+// truncateLanguageTag removes the last subtag of tag, along with a single-character subtag left at the end
+// (the introducer of an extension or private use): zh-Hant-CN-x-private1 becomes zh-Hant-CN
+func truncateLanguageTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+
+	tag = tag[:i]
+	if j := strings.LastIndex(tag, "-"); j >= 0 && j == len(tag)-2 {
+		tag = tag[:j]
+	}
+	return tag
+}
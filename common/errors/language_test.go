@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupLanguage(t *testing.T) {
+	available := []string{"de", "en", "es", "fr", "pt-BR"}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "empty", acceptLanguage: "", expected: "en"},
+		{name: "exact", acceptLanguage: "fr", expected: "fr"},
+		{name: "case_insensitive", acceptLanguage: "FR", expected: "fr"},
+		{name: "region_truncated", acceptLanguage: "fr-CA", expected: "fr"},
+		{name: "underscore", acceptLanguage: "es_MX", expected: "es"},
+		{name: "private_use_truncated", acceptLanguage: "de-DE-x-phonebk", expected: "de"},
+		{name: "region_kept", acceptLanguage: "pt-BR", expected: "pt-BR"},
+		{name: "range_not_extended", acceptLanguage: "pt", expected: "en"},
+		{name: "quality_order", acceptLanguage: "fr;q=0.9, en;q=0.8", expected: "fr"},
+		{name: "highest_quality_first", acceptLanguage: "en;q=0.5, de;q=0.7", expected: "de"},
+		{name: "header_order_on_ties", acceptLanguage: "es, fr", expected: "es"},
+		{name: "unknown_then_known", acceptLanguage: "ja-JP, ja;q=0.9, fr;q=0.1", expected: "fr"},
+		{name: "refused", acceptLanguage: "fr;q=0, de;q=0.1", expected: "de"},
+		{name: "wildcard_skipped", acceptLanguage: "*, es;q=0.5", expected: "es"},
+		{name: "nothing_matches", acceptLanguage: "ja, zh-Hant-TW", expected: "en"},
+		{name: "invalid_quality", acceptLanguage: "fr;q=high, de;q=0.2", expected: "de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LookupLanguage(tt.acceptLanguage, available, "en"))
+		})
+	}
+}
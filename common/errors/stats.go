@@ -7,18 +7,70 @@ var (
 	StatsNotFoundError = ControllerError{
 		Name:          "StatsNotFoundError",
 		HttpErrorCode: http.StatusNoContent,
-		Translation: Translation{
-			Fr: "Aucune statistique disponible.",
-			En: "No statistics available.",
-		},
+	}
+
+	ValidationStatsTopNError = ControllerError{
+		Name:          "ValidationStatsTopNError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsOffsetError = ControllerError{
+		Name:          "ValidationStatsOffsetError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsWindowError = ControllerError{
+		Name:          "ValidationStatsWindowError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsTimeRangeError = ControllerError{
+		Name:          "ValidationStatsTimeRangeError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsFormatError = ControllerError{
+		Name:          "ValidationStatsFormatError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsImportError = ControllerError{
+		Name:          "ValidationStatsImportError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	StatsImportFailedError = ControllerError{
+		Name:          "StatsImportFailedError",
+		HttpErrorCode: http.StatusInternalServerError,
+	}
+
+	ValidationStatsIDError = ControllerError{
+		Name:          "ValidationStatsIDError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	StatsEntryNotFoundError = ControllerError{
+		Name:          "StatsEntryNotFoundError",
+		HttpErrorCode: http.StatusNotFound,
+	}
+
+	ValidationStatsDeletionParamError = ControllerError{
+		Name:          "ValidationStatsDeletionParamError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	ValidationStatsDeletionLimitError = ControllerError{
+		Name:          "ValidationStatsDeletionLimitError",
+		HttpErrorCode: http.StatusBadRequest,
+	}
+
+	StatsDeletionError = ControllerError{
+		Name:          "StatsDeletionError",
+		HttpErrorCode: http.StatusInternalServerError,
 	}
 
 	StatsRetrievalError = ControllerError{
 		Name:          "StatsRetrievalError",
 		HttpErrorCode: http.StatusInternalServerError,
-		Translation: Translation{
-			Fr: "Erreur lors de la récupération des statistiques.",
-			En: "Failed to retrieve statistics.",
-		},
 	}
 )
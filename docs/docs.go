@@ -23,76 +23,696 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/v1/admin/stats/export": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Returns every stats entry (parameter set, all-time hit count, first and last request times), most hits first.\nHits per time bucket are not exported. Requires the admin token.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export FizzBuzz statistics",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/stats/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Imports a document produced by the export endpoint. The hit counts of parameter sets already stored are\nsummed, keeping the earliest first request time. Imported hits only count in all-time stats, not in\nwindowed ones. The whole document is validated first: when an entry is invalid nothing is imported.\nThe format is read from the format query parameter, then from Content-Type (text/csv or JSON).",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import FizzBuzz statistics",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Import format, overrides Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Exported statistics",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsExport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/fizzbuzz": {
+            "get": {
+                "description": "Same as POST /api/v1/fizzbuzz with the legacy parameters passed in the query string, so results can be bookmarked and cached.\nResponses carry a strong ETag derived from the parameters and format; a matching If-None-Match gets a 304 (the hit still counts in stats).",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/x-ndjson",
+                    "text/plain",
+                    "text/csv"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Generate FizzBuzz sequence (cacheable)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "First divisor",
+                        "name": "int1",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Second divisor",
+                        "name": "int2",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Upper limit of the sequence",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replacement for multiples of int1",
+                        "name": "str1",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replacement for multiples of int2",
+                        "name": "str2",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First number of the returned range",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last number of the returned range",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum values per page",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language tag whose digits and separators render the numbers, or auto for the Accept-Language header",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Replace the words with the presets of the locale (fizz, buzz)",
+                        "name": "localize_words",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeral system of the numbers: decimal (default), binary, octal, hex, base2 to base36, or roman (up to 3999); only decimal with locale",
+                        "name": "number_format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "ndjson",
+                            "text",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides Accept",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Unsupported response format (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Generates a customized FizzBuzz sequence based on provided parameters",
+                "description": "Generates a customized FizzBuzz sequence based on provided parameters.\nEither pass \"rules\" (divisor/word pairs applied in order) or the legacy int1/str1 + int2/str2 shorthand.\nOptional start/end restrict the result to a range of the sequence and page_size splits it into pages:\nonly the returned page is computed, and next_cursor is set until the last page (pass it back as \"cursor\").\nThe representation is chosen with the format query parameter (json, xml, ndjson, text, csv) or the Accept header.\nLine-based formats (ndjson, text, csv) report pagination in the X-Total-Count and X-Next-Cursor headers.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/x-ndjson",
+                    "text/plain",
+                    "text/csv"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Generate FizzBuzz sequence",
+                "parameters": [
+                    {
+                        "description": "FizzBuzz parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "ndjson",
+                            "text",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides Accept",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Unsupported response format (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/fizzbuzz/batch": {
+            "post": {
+                "description": "Accepts an array of FizzBuzz requests (1 to 1000, producing at most 1000000 values in total).\nEach item is validated and generated independently: the response lists, in order, either its result or its error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Generate FizzBuzz sequences in batch",
+                "parameters": [
+                    {
+                        "description": "FizzBuzz parameter sets",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/fizzbuzz/stream": {
+            "post": {
+                "description": "Streams a customized FizzBuzz sequence as newline-delimited JSON (one JSON string per line), using constant memory.\nAccepts the same parameters as POST /api/v1/fizzbuzz with a limit of up to 100000000. Generation stops when the client disconnects.\nstart/end restrict the streamed range; page_size and cursor are ignored, whatever their value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Stream FizzBuzz sequence",
+                "parameters": [
+                    {
+                        "description": "FizzBuzz parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One JSON-encoded value per line",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats": {
+            "get": {
+                "description": "Returns statistics about the most frequently requested FizzBuzz parameters.\nWith window, or since and/or until, only hits in that time range are counted. Ranges are\nrounded to the configured bucket granularity (minute, hour or day) and limited by bucket retention.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get FizzBuzz statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Count hits of the last duration, e.g. 30m, 1h, 24h",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Count hits from this RFC 3339 timestamp",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Count hits before this RFC 3339 timestamp (default: now)",
+                        "name": "until",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "No statistics available yet"
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "With all=true alone, deletes every stats entry and its hits per time bucket. With the parameters of a\nFizzBuzz request, in the query string (legacy form) or as a JSON body (either form), only deletes the\nentry of that parameter set. Any other parameter is rejected.\nDeletions cannot be undone and are recorded in the audit log. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Delete FizzBuzz statistics",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Delete every entry, without any other parameter",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First divisor",
+                        "name": "int1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Second divisor",
+                        "name": "int2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Upper limit of the sequence",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replacement for multiples of int1",
+                        "name": "str1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replacement for multiples of int2",
+                        "name": "str2",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Parameter set to delete, to pass rules",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsDeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No entry for these parameters (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/store": {
+            "get": {
+                "description": "Returns the number of distinct requests stored and, with memory storage, its bounds and how many\nentries were evicted (max_entries reached) or expired (entry_ttl without requests) since startup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get stats store metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsStoreMetrics"
+                        }
+                    },
+                    "500": {
+                        "description": "Service error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/top": {
+            "get": {
+                "description": "Returns a page of the most frequently requested FizzBuzz parameters.\nEntries with the same hit count are ordered by first request time, so pages are stable across calls and storage backends.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "fizzbuzz"
+                    "stats"
                 ],
-                "summary": "Generate FizzBuzz sequence",
+                "summary": "Get top FizzBuzz statistics",
                 "parameters": [
                     {
-                        "description": "FizzBuzz parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
-                        }
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of entries (1-100)",
+                        "name": "n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzResponse"
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsTopResponse"
                         }
                     },
                     "400": {
                         "description": "Validation error message (translated)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Service error message (translated)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/stats": {
-            "get": {
-                "description": "Returns statistics about the most frequently requested FizzBuzz parameters",
+        "/api/v1/stats/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Deletes the stats entry with the id returned by GET /api/v1/stats and /api/v1/stats/top, and its hits\nper time bucket. Deletions cannot be undone and are recorded in the audit log. Requires the admin token.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "stats"
                 ],
-                "summary": "Get FizzBuzz statistics",
+                "summary": "Delete a FizzBuzz statistics entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsResponse"
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsDeleteResult"
                         }
                     },
-                    "204": {
-                        "description": "No statistics available yet"
+                    "400": {
+                        "description": "Validation error message (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No entry with this ID (translated)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
+                        }
                     },
                     "500": {
                         "description": "Service error message (translated)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse"
                         }
                     }
                 }
@@ -120,38 +740,121 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "github_com_julietteengel_fizzbuzz-api_internal_model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "description": "Stable code: the name of the ControllerError, e.g. ValidationLimitError",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Invalid fields, for validation errors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ValidationError"
+                    }
+                },
+                "message": {
+                    "description": "Translated according to Accept-Language",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "X-Request-ID, to find the request in the logs",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzBatchItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ValidationErrorResponse"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "response": {
+                    "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzResponse"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzBatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzBatchItem"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest": {
             "type": "object",
             "required": [
-                "int1",
-                "int2",
-                "limit",
-                "str1",
-                "str2"
+                "limit"
             ],
             "properties": {
-                "int1": {
+                "cursor": {
+                    "type": "string"
+                },
+                "end": {
                     "type": "integer",
                     "minimum": 1
                 },
+                "int1": {
+                    "type": "integer"
+                },
                 "int2": {
+                    "type": "integer"
+                },
+                "limit": {
                     "type": "integer",
                     "minimum": 1
                 },
-                "limit": {
+                "locale": {
+                    "description": "Locale renders the numbers (digits, thousands separators) of a language, or of the Accept-Language header with \"auto\";\nLocalizeWords also replaces the words that have a preset in the locale (fizz, buzz)",
+                    "type": "string"
+                },
+                "localize_words": {
+                    "type": "boolean"
+                },
+                "number_format": {
+                    "description": "NumberFormat writes numbers in decimal (default), binary, octal, hex, base2 to base36 or roman; only decimal goes with Locale",
+                    "type": "string"
+                },
+                "page_size": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "rules": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.Rule"
+                    }
+                },
+                "start": {
                     "type": "integer",
-                    "maximum": 10000,
                     "minimum": 1
                 },
                 "str1": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "type": "string"
                 },
                 "str2": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "type": "string"
                 }
             }
         },
@@ -161,11 +864,23 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "description": "Set while pages remain; pass it back as \"cursor\"",
+                    "type": "string"
+                },
                 "result": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "start": {
+                    "description": "Number whose value is Result[0]",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Values in the requested range, across all pages",
+                    "type": "integer"
                 }
             }
         },
@@ -186,16 +901,204 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.Rule": {
+            "type": "object",
+            "required": [
+                "divisor",
+                "word"
+            ],
+            "properties": {
+                "divisor": {
+                    "type": "integer"
+                },
+                "word": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsDeleteResult": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Distinct parameter sets deleted",
+                    "type": "integer"
+                },
+                "hits": {
+                    "description": "Their hits",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsExport": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsExportEntry"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsExportEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "First request",
+                    "type": "string"
+                },
+                "hit_count": {
+                    "type": "integer"
+                },
+                "request": {
+                    "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                },
+                "updated_at": {
+                    "description": "Last request",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsImportResult": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Distinct parameter sets imported",
+                    "type": "integer"
+                },
+                "hits": {
+                    "description": "Hits added",
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_julietteengel_fizzbuzz-api_internal_model.StatsResponse": {
             "type": "object",
             "properties": {
                 "hit_count": {
                     "type": "integer"
                 },
+                "id": {
+                    "description": "Identifies the entry in DELETE /stats/{id}",
+                    "type": "integer"
+                },
                 "request": {
                     "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.FizzBuzzRequest"
+                },
+                "since": {
+                    "description": "Set for windowed queries: hit_count only covers since..until",
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
                 }
             }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsStoreMetrics": {
+            "type": "object",
+            "properties": {
+                "dropped_hits": {
+                    "description": "Buffered hits dropped because flushes kept failing or the database refused them",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "entry_ttl": {
+                    "type": "string"
+                },
+                "eviction_policy": {
+                    "type": "string"
+                },
+                "evictions": {
+                    "description": "Entries removed to stay under max_entries",
+                    "type": "integer"
+                },
+                "expirations": {
+                    "description": "Entries removed after entry_ttl without requests",
+                    "type": "integer"
+                },
+                "flush_errors": {
+                    "description": "Failed batch writes, retried at the next flush",
+                    "type": "integer"
+                },
+                "max_entries": {
+                    "type": "integer"
+                },
+                "pending_hits": {
+                    "description": "Hits buffered in memory, not yet written to the database",
+                    "type": "integer"
+                },
+                "snapshot_errors": {
+                    "description": "Failed saves of the memory store, retried at the next snapshot",
+                    "type": "integer"
+                },
+                "storage": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.StatsTopResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.StatsResponse"
+                    }
+                },
+                "n": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.ValidationError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_julietteengel_fizzbuzz-api_internal_model.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_julietteengel_fizzbuzz-api_internal_model.ValidationError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin endpoints are enabled by setting ADMIN_TOKEN. Send \"Bearer \u003cADMIN_TOKEN\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"crypto/subtle"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
+)
+
+// AdminAuth only lets through requests carrying "Authorization: Bearer <token>"
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Validator: func(key string, ctx echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1, nil
+		},
+		ErrorHandler: func(err error, ctx echo.Context) error {
+			return errors.WrapErrorHTTP(ctx, nil, errors.UnauthorizedError)
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+	}{
+		{name: "valid_token", authorization: "Bearer s3cret", expectedCode: http.StatusOK},
+		{name: "wrong_token", authorization: "Bearer guess", expectedCode: http.StatusUnauthorized},
+		{name: "token_prefix", authorization: "Bearer s3cre", expectedCode: http.StatusUnauthorized},
+		{name: "missing_scheme", authorization: "s3cret", expectedCode: http.StatusUnauthorized},
+		{name: "missing_header", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/admin", func(ctx echo.Context) error {
+				return ctx.NoContent(http.StatusOK)
+			}, AdminAuth("s3cret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+const (
+	headerETag         = "ETag"
+	headerIfNoneMatch  = "If-None-Match"
+	headerCacheControl = "Cache-Control"
+
+	// fizzBuzzCacheControl lets browsers and shared caches keep results, but have them revalidate every reuse
+	// with If-None-Match: the 304 is cheap, and the hit reaches the server so that stats count it
+	fizzBuzzCacheControl = "public, no-cache"
+)
+
+// fizzBuzzETag derives a strong entity tag from everything that shapes the response body.
+func fizzBuzzETag(request model.FizzBuzzRequest, format responseFormat) string {
+	params, _ := json.Marshal(request) // FizzBuzzRequest always marshals
+	sum := sha256.Sum256(append(params, format...))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag applies the If-None-Match comparison of RFC 9110: weak comparison against
+// a comma-separated list of tags, where "*" matches any current representation.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
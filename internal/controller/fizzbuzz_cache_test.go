@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func TestFizzBuzzETag(t *testing.T) {
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+	other := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 16, Str1: "fizz", Str2: "buzz"}
+
+	etag := fizzBuzzETag(request, formatJSON)
+
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, etag, fizzBuzzETag(request, formatJSON))
+	assert.NotEqual(t, etag, fizzBuzzETag(other, formatJSON))
+	assert.NotEqual(t, etag, fizzBuzzETag(request, formatCSV))
+}
+
+func TestMatchesETag(t *testing.T) {
+	etag := `"abc"`
+
+	assert.True(t, matchesETag(`"abc"`, etag))
+	assert.True(t, matchesETag(`W/"abc"`, etag))
+	assert.True(t, matchesETag(`"xyz", "abc"`, etag))
+	assert.True(t, matchesETag(`*`, etag))
+	assert.False(t, matchesETag(``, etag))
+	assert.False(t, matchesETag(`"xyz"`, etag))
+	assert.False(t, matchesETag(`abc`, etag))
+}
@@ -1,63 +1,205 @@
 package controller
 
 import (
+	"bufio"
+	"encoding/json"
+	stderrors "errors"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 
 	"github.com/julietteengel/fizzbuzz-api/common/errors"
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 	"github.com/julietteengel/fizzbuzz-api/internal/service"
 )
 
+const (
+	// maxStreamLimit caps streamed sequences, which are never held in memory
+	maxStreamLimit = config.MaxStreamLimit
+	// maxBatchSize caps the number of requests in a batch
+	maxBatchSize = 1000
+	// maxBatchValues caps the number of values generated for a whole batch
+	maxBatchValues = 1000000
+	// streamFlushInterval is the number of values written between two flushes of a stream
+	streamFlushInterval = 1000
+
+	mimeApplicationNDJSON = "application/x-ndjson"
+)
+
 type FizzBuzzController struct {
 	service service.IFizzBuzzService
+	limits  fizzBuzzLimits
 }
 
-func NewFizzBuzzController(service service.IFizzBuzzService) *FizzBuzzController {
+func NewFizzBuzzController(service service.IFizzBuzzService, cfg *config.Config) *FizzBuzzController {
 	return &FizzBuzzController{
 		service: service,
+		limits:  newFizzBuzzLimits(cfg),
 	}
 }
 
 // GenerateFizzBuzz generates a FizzBuzz sequence with custom parameters.
 // @Summary Generate FizzBuzz sequence
-// @Description Generates a customized FizzBuzz sequence based on provided parameters
+// @Description Generates a customized FizzBuzz sequence based on provided parameters.
+// @Description Either pass "rules" (divisor/word pairs applied in order) or the legacy int1/str1 + int2/str2 shorthand.
+// @Description Optional start/end restrict the result to a range of the sequence and page_size splits it into pages:
+// @Description only the returned page is computed, and next_cursor is set until the last page (pass it back as "cursor").
+// @Description The representation is chosen with the format query parameter (json, xml, ndjson, text, csv) or the Accept header.
+// @Description Line-based formats (ndjson, text, csv) report pagination in the X-Total-Count and X-Next-Cursor headers.
 // @Tags fizzbuzz
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/x-ndjson,plain,text/csv
 // @Param request body model.FizzBuzzRequest true "FizzBuzz parameters"
+// @Param format query string false "Response format, overrides Accept" Enums(json, xml, ndjson, text, csv)
 // @Success 200 {object} model.FizzBuzzResponse
-// @Failure 400 {string} string "Validation error message (translated)"
-// @Failure 500 {string} string "Service error message (translated)"
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 406 {object} model.ErrorResponse "Unsupported response format (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
 // @Router /api/v1/fizzbuzz [post]
 func (c *FizzBuzzController) GenerateFizzBuzz(ctx echo.Context) error {
 	var request model.FizzBuzzRequest
 
 	if err := ctx.Bind(&request); err != nil {
-		return errors.WrapErrorHTTP(ctx, err, errors.InvalidRequestError)
+		return bindError(ctx, err)
+	}
+
+	format, err := c.limits.validateAndNegotiate(ctx, request)
+	if err != nil {
+		return err
+	}
+	request = localizeRequest(ctx, request)
+
+	response, err := c.service.GenerateFizzBuzz(ctx.Request().Context(), request)
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.ServiceError)
+	}
+
+	return writeFizzBuzzResponse(ctx, format, response)
+}
+
+// GenerateFizzBuzzBatch generates several FizzBuzz sequences in one call.
+// @Summary Generate FizzBuzz sequences in batch
+// @Description Accepts an array of FizzBuzz requests (1 to 1000, producing at most 1000000 values in total).
+// @Description Each item is validated and generated independently: the response lists, in order, either its result or its error.
+// @Tags fizzbuzz
+// @Accept json
+// @Produce json
+// @Param request body []model.FizzBuzzRequest true "FizzBuzz parameter sets"
+// @Success 200 {object} model.FizzBuzzBatchResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid batch (translated)"
+// @Router /api/v1/fizzbuzz/batch [post]
+func (c *FizzBuzzController) GenerateFizzBuzzBatch(ctx echo.Context) error {
+	var requests []model.FizzBuzzRequest
+
+	if err := ctx.Bind(&requests); err != nil {
+		return bindError(ctx, err)
+	}
+
+	if len(requests) == 0 || len(requests) > maxBatchSize {
+		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationBatchSizeError)
+	}
+
+	// Reject oversized batches up front rather than after generating most of them
+	validationErrs := make([]error, len(requests))
+	totalValues := 0
+	for i, request := range requests {
+		validationErrs[i] = c.limits.validateBufferedRequest(ctx, request)
+		if validationErrs[i] == nil {
+			totalValues += pageLength(request)
+		}
+	}
+	if totalValues > maxBatchValues {
+		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationBatchValuesError)
 	}
 
-	// Validation spécifique par champ
-	if request.Int1 <= 0 {
-		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationInt1Error)
+	response := model.FizzBuzzBatchResponse{
+		Items: make([]model.FizzBuzzBatchItem, 0, len(requests)),
+	}
+
+	for i, request := range requests {
+		item := model.FizzBuzzBatchItem{Index: i}
+
+		if validationErrs[i] != nil {
+			item.Error = validationErrorResponse(ctx, validationErrs[i])
+		} else if result, err := c.service.GenerateFizzBuzz(ctx.Request().Context(), localizeRequest(ctx, request)); err != nil {
+			log.Errorf("Error %s for batch item %d: %v", errors.ServiceError.Name, i, err)
+			item.Error = &model.ValidationErrorResponse{
+				Error:   errors.ServiceError.Name,
+				Message: errors.Translate(ctx, errors.ServiceError),
+			}
+		} else {
+			item.Response = result
+		}
+
+		if item.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Items = append(response.Items, item)
 	}
-	
-	if request.Int2 <= 0 {
-		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationInt2Error)
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetFizzBuzz generates a FizzBuzz sequence from query parameters, with HTTP caching.
+// @Summary Generate FizzBuzz sequence (cacheable)
+// @Description Same as POST /api/v1/fizzbuzz with the legacy parameters passed in the query string, so results can be bookmarked and cached.
+// @Description Responses carry a strong ETag derived from the parameters and format; a matching If-None-Match gets a 304 (the hit still counts in stats).
+// @Tags fizzbuzz
+// @Produce json,xml,application/x-ndjson,plain,text/csv
+// @Param int1 query int true "First divisor"
+// @Param int2 query int true "Second divisor"
+// @Param limit query int true "Upper limit of the sequence"
+// @Param str1 query string true "Replacement for multiples of int1"
+// @Param str2 query string true "Replacement for multiples of int2"
+// @Param start query int false "First number of the returned range"
+// @Param end query int false "Last number of the returned range"
+// @Param page_size query int false "Maximum values per page"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param locale query string false "Language tag whose digits and separators render the numbers, or auto for the Accept-Language header"
+// @Param localize_words query bool false "Replace the words with the presets of the locale (fizz, buzz)"
+// @Param number_format query string false "Numeral system of the numbers: decimal (default), binary, octal, hex, base2 to base36, or roman (up to 3999); only decimal with locale"
+// @Param format query string false "Response format, overrides Accept" Enums(json, xml, ndjson, text, csv)
+// @Success 200 {object} model.FizzBuzzResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 406 {object} model.ErrorResponse "Unsupported response format (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/fizzbuzz [get]
+func (c *FizzBuzzController) GetFizzBuzz(ctx echo.Context) error {
+	var request model.FizzBuzzRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return bindError(ctx, err)
 	}
-	
-	if request.Limit <= 0 || request.Limit > 10000 {
-		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationLimitError)
+
+	format, err := c.limits.validateAndNegotiate(ctx, request)
+	if err != nil {
+		return err
 	}
-	
-	if len(request.Str1) == 0 || len(request.Str1) > 100 {
-		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStr1Error)
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderVary, echo.HeaderAccept)
+	if strings.EqualFold(request.Locale, localeAuto) {
+		header.Add(echo.HeaderVary, "Accept-Language")
 	}
-	
-	if len(request.Str2) == 0 || len(request.Str2) > 100 {
-		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStr2Error)
+	request = localizeRequest(ctx, request)
+
+	// The output only depends on the parameters, locale and format, so the tag never has to change;
+	// caches still revalidate it on every reuse, see fizzBuzzCacheControl
+	etag := fizzBuzzETag(request, format)
+	header.Set(headerETag, etag)
+	header.Set(headerCacheControl, fizzBuzzCacheControl)
+
+	if matchesETag(ctx.Request().Header.Get(headerIfNoneMatch), etag) {
+		c.service.RecordRequest(ctx.Request().Context(), request)
+		return ctx.NoContent(http.StatusNotModified)
 	}
 
 	response, err := c.service.GenerateFizzBuzz(ctx.Request().Context(), request)
@@ -65,7 +207,72 @@ func (c *FizzBuzzController) GenerateFizzBuzz(ctx echo.Context) error {
 		return errors.WrapErrorHTTP(ctx, err, errors.ServiceError)
 	}
 
-	return ctx.JSON(http.StatusOK, response)
+	return writeFizzBuzzResponse(ctx, format, response)
+}
+
+// StreamFizzBuzz streams a FizzBuzz sequence as NDJSON while it is being computed.
+// @Summary Stream FizzBuzz sequence
+// @Description Streams a customized FizzBuzz sequence as newline-delimited JSON (one JSON string per line), using constant memory.
+// @Description Accepts the same parameters as POST /api/v1/fizzbuzz with a limit of up to 100000000. Generation stops when the client disconnects.
+// @Description start/end restrict the streamed range; page_size and cursor are ignored, whatever their value.
+// @Tags fizzbuzz
+// @Accept json
+// @Produce application/x-ndjson
+// @Param request body model.FizzBuzzRequest true "FizzBuzz parameters"
+// @Success 200 {string} string "One JSON-encoded value per line"
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Router /api/v1/fizzbuzz/stream [post]
+func (c *FizzBuzzController) StreamFizzBuzz(ctx echo.Context) error {
+	var request model.FizzBuzzRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return bindError(ctx, err)
+	}
+
+	// Streams ignore pagination, so a page_size above MAX_LIMIT is no reason to reject them
+	request.PageSize, request.Cursor = 0, ""
+
+	if err := c.limits.validateFizzBuzzRequest(ctx, request, maxStreamLimit, errors.ValidationSequenceLimitError); err != nil {
+		return validationErrorHTTP(ctx, err)
+	}
+	request = localizeRequest(ctx, request)
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, mimeApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	// Buffer writes and flush periodically so values reach the client as chunks without a syscall per value
+	writer := bufio.NewWriter(res)
+	encoder := json.NewEncoder(writer)
+	written := 0
+
+	err := c.service.StreamFizzBuzz(ctx.Request().Context(), request, func(value string) error {
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+
+		written++
+		if written%streamFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		// Headers are already sent: the only thing left to do is stop writing
+		if ctx.Request().Context().Err() == nil {
+			log.Errorf("FizzBuzz stream interrupted after %d values: %v", written, err)
+		}
+		return nil
+	}
+
+	res.Flush()
+	return nil
 }
 
 // HealthCheck returns the health status of the API.
@@ -81,3 +288,142 @@ func (c *FizzBuzzController) HealthCheck(ctx echo.Context) error {
 		Timestamp: time.Now(),
 	})
 }
+
+// bindError reports a request that cannot be parsed, with the reason given by the binder as detail.
+// Only the message of Echo's HTTPError is sent: the error it wraps exposes the internals of the decoder.
+func bindError(ctx echo.Context, err error) error {
+	var he *echo.HTTPError
+	if stderrors.As(err, &he) {
+		if message, ok := he.Message.(string); ok {
+			return errors.WrapErrorHTTPWithDetails(ctx, err, errors.InvalidRequestError, map[string]string{"cause": message})
+		}
+	}
+	return errors.WrapErrorHTTP(ctx, err, errors.InvalidRequestError)
+}
+
+// validateFizzBuzzRequest checks every field of request and returns fieldErrors listing the invalid ones:
+// first the `validate` tags of model.FizzBuzzRequest, then the rules involving several fields.
+// maxLimit and limitError differ between the buffered and streaming endpoints.
+func (l fizzBuzzLimits) validateFizzBuzzRequest(ctx echo.Context, request model.FizzBuzzRequest, maxLimit int, limitError errors.ControllerError) error {
+	// "rules": [] means no rules, but the required_without tags only look at nil
+	if len(request.Rules) == 0 {
+		request.Rules = nil
+	}
+
+	var invalid fieldErrors
+	if err := ctx.Validate(request); err != nil {
+		var tagErrors validator.ValidationErrors
+		if !stderrors.As(err, &tagErrors) {
+			return err
+		}
+		for _, tagErr := range tagErrors {
+			invalid.addTagError(tagErr, l.tagError(tagErr, limitError))
+		}
+	}
+
+	if len(request.Rules) > 0 && request.HasLegacyParams() {
+		invalid.add("rules", nil, errors.ValidationRulesConflictError)
+	}
+
+	if request.Limit > maxLimit {
+		invalid.add("limit", request.Limit, limitError)
+	}
+
+	if _, ok := negotiateLocale(ctx, request.Locale); !ok {
+		invalid.add("locale", request.Locale, errors.ValidationLocaleError)
+	}
+
+	// The range is only compared to valid bounds
+	if !invalid.has("limit", "start", "end") {
+		start, end := request.Window()
+		if end > request.Limit {
+			invalid.add("end", request.End, errors.ValidationRangeError)
+		} else if start > end {
+			invalid.add("start", request.Start, errors.ValidationRangeError)
+		}
+	}
+
+	base, ok := service.NumberBase(request.NumberFormat)
+	if !ok {
+		invalid.add("number_format", request.NumberFormat, errors.ValidationNumberFormatError)
+	}
+
+	// A locale writes numbers with its own decimal digits, which no other format has
+	if ok && base != 10 && request.Locale != "" {
+		invalid.add("number_format", request.NumberFormat, errors.ValidationNumberFormatLocaleError)
+	} else if _, end := request.Window(); ok && base == 0 && end > service.MaxRomanNumeral && !invalid.has("limit", "start", "end") {
+		// Roman numerals don't go past MaxRomanNumeral: the last number of a valid range must not either
+		invalid.add("number_format", request.NumberFormat, errors.ValidationRomanNumeralError)
+	}
+
+	if len(invalid) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// validateAndNegotiate runs every check of the buffered endpoints and picks the response format.
+func (l fizzBuzzLimits) validateAndNegotiate(ctx echo.Context, request model.FizzBuzzRequest) (responseFormat, error) {
+	if err := l.validateBufferedRequest(ctx, request); err != nil {
+		return "", validationErrorHTTP(ctx, err)
+	}
+
+	format, ok := negotiateFormat(ctx)
+	if !ok {
+		return "", errors.WrapErrorHTTP(ctx, nil, errors.NotAcceptableError)
+	}
+
+	return format, nil
+}
+
+// validateBufferedRequest checks a request whose result is returned as a single document.
+func (l fizzBuzzLimits) validateBufferedRequest(ctx echo.Context, request model.FizzBuzzRequest) error {
+	// Windowed requests only hold one page in memory, so the sequence itself may be as long as a stream
+	requestMaxLimit, limitError := l.maxLimit, l.limitError
+	if request.IsWindowed() {
+		requestMaxLimit, limitError = maxStreamLimit, errors.ValidationSequenceLimitError
+	}
+
+	var invalid fieldErrors
+	if err := l.validateFizzBuzzRequest(ctx, request, requestMaxLimit, limitError); err != nil && !stderrors.As(err, &invalid) {
+		return err
+	}
+
+	invalid = l.validatePage(request, invalid)
+	if len(invalid) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// pageLength returns how many values a valid buffered request produces at most
+func pageLength(request model.FizzBuzzRequest) int {
+	start, end := request.Window()
+	length := end - start + 1
+	if request.PageSize > 0 && request.PageSize < length {
+		length = request.PageSize
+	}
+	return length
+}
+
+// validatePage adds to invalid the errors making a single response exceed maxLimit values, or the cursor
+// point outside the requested range. Both need a valid range, so nothing is checked without one.
+func (l fizzBuzzLimits) validatePage(request model.FizzBuzzRequest, invalid fieldErrors) fieldErrors {
+	if invalid.has("limit", "start", "end") {
+		return invalid
+	}
+
+	start, end := request.Window()
+	if request.PageSize == 0 && end-start+1 > l.maxLimit {
+		invalid.add("page_size", nil, l.errorsByField["PageSize"])
+	}
+
+	if request.Cursor != "" {
+		position, err := service.DecodeCursor(request.Cursor)
+		if err != nil || position < start || position > end {
+			invalid.add("cursor", request.Cursor, errors.ValidationCursorError)
+		}
+	}
+
+	return invalid
+}
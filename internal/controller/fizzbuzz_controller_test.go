@@ -2,23 +2,40 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
 	"github.com/julietteengel/fizzbuzz-api/internal/mocks"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
+	"github.com/julietteengel/fizzbuzz-api/internal/service"
 )
 
-func TestFizzBuzzController_GenerateFizzBuzz_Success(t *testing.T) {
+// newTestEcho returns an Echo with the validator of the server
+func newTestEcho() *echo.Echo {
 	e := echo.New()
+	e.Validator = NewRequestValidator(newTestConfig())
+	return e
+}
+
+// newTestConfig returns the default bounds of config.Load
+func newTestConfig() *config.Config {
+	return &config.Config{App: config.AppConfig{MaxLimit: 10000, MinStringLength: 1, MaxStringLength: 100, MinDivisor: 1}}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_Success(t *testing.T) {
+	e := newTestEcho()
 	mockService := mocks.NewMockIFizzBuzzService(t)
-	controller := NewFizzBuzzController(mockService)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
 
 	request := model.FizzBuzzRequest{
 		Int1:  3,
@@ -57,9 +74,9 @@ func TestFizzBuzzController_GenerateFizzBuzz_Success(t *testing.T) {
 }
 
 func TestFizzBuzzController_GenerateFizzBuzz_ValidationErrors(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIFizzBuzzService(t)
-	controller := NewFizzBuzzController(mockService)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
 
 	tests := []struct {
 		name           string
@@ -187,6 +204,118 @@ func TestFizzBuzzController_GenerateFizzBuzz_ValidationErrors(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "invalid_rules_mixed_with_legacy",
+			request: model.FizzBuzzRequest{
+				Int1:  3,
+				Limit: 15,
+				Rules: []model.Rule{{Divisor: 5, Word: "buzz"}},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_rule_divisor_zero",
+			request: model.FizzBuzzRequest{
+				Limit: 15,
+				Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 0, Word: "buzz"}},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_rule_word_empty",
+			request: model.FizzBuzzRequest{
+				Limit: 15,
+				Rules: []model.Rule{{Divisor: 3, Word: ""}},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_start_after_end",
+			request: model.FizzBuzzRequest{
+				Int1:  3,
+				Int2:  5,
+				Limit: 15,
+				Str1:  "fizz",
+				Str2:  "buzz",
+				Start: 10,
+				End:   5,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_end_above_limit",
+			request: model.FizzBuzzRequest{
+				Int1:  3,
+				Int2:  5,
+				Limit: 15,
+				Str1:  "fizz",
+				Str2:  "buzz",
+				End:   16,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_window_too_large_without_page_size",
+			request: model.FizzBuzzRequest{
+				Int1:  3,
+				Int2:  5,
+				Limit: 1000000,
+				Str1:  "fizz",
+				Str2:  "buzz",
+				Start: 1,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_page_size_too_high",
+			request: model.FizzBuzzRequest{
+				Int1:     3,
+				Int2:     5,
+				Limit:    1000000,
+				Str1:     "fizz",
+				Str2:     "buzz",
+				PageSize: 10001,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_cursor",
+			request: model.FizzBuzzRequest{
+				Int1:   3,
+				Int2:   5,
+				Limit:  15,
+				Str1:   "fizz",
+				Str2:   "buzz",
+				Cursor: "garbage",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_cursor_outside_range",
+			request: model.FizzBuzzRequest{
+				Int1:   3,
+				Int2:   5,
+				Limit:  15,
+				Str1:   "fizz",
+				Str2:   "buzz",
+				Cursor: service.EncodeCursor(16),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			// PostgreSQL text cannot store NUL: stats could not record the request
+			name:           "invalid_rule_word_nul",
+			request:        model.FizzBuzzRequest{Limit: 15, Rules: []model.Rule{{Divisor: 3, Word: "fi\x00zz"}}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_too_many_rules",
+			request: model.FizzBuzzRequest{
+				Limit: 15,
+				Rules: make([]model.Rule, 11),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -212,10 +341,333 @@ func TestFizzBuzzController_GenerateFizzBuzz_ValidationErrors(t *testing.T) {
 	}
 }
 
+func TestFizzBuzzController_GenerateFizzBuzz_AllFieldErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		acceptLanguage string
+		expectedError  string
+		expectedFields []model.ValidationError
+	}{
+		{
+			name:          "legacy_fields",
+			body:          `{"int1": -3, "limit": 20000, "str1": "fizz", "str2": ""}`,
+			expectedError: "ValidationInt1Error",
+			expectedFields: []model.ValidationError{
+				{Field: "int1", Message: "Parameter int1 must be 1 or more.", Value: "-3"},
+				{Field: "int2", Message: "Parameter int2 must be 1 or more."},
+				{Field: "str2", Message: "Parameter str2 must be between 1 and 100 characters of valid UTF-8 text, without NUL."},
+				{Field: "limit", Message: "Parameter limit must be between 1 and 10000.", Value: "20000"},
+			},
+		},
+		{
+			name:           "rules_translated",
+			body:           `{"limit": 15, "rules": [{"divisor": 3, "word": "fizz"}, {"divisor": 0, "word": ""}], "start": 10, "end": 5}`,
+			acceptLanguage: "fr",
+			expectedError:  "ValidationRuleDivisorError",
+			expectedFields: []model.ValidationError{
+				{Field: "rules[1].divisor", Message: "Le diviseur de chaque règle doit être supérieur ou égal à 1."},
+				{Field: "rules[1].word", Message: "Le mot de chaque règle doit contenir entre 1 et 100 caractères de texte UTF-8 valide, sans NUL."},
+				{Field: "start", Message: "Les paramètres start et end doivent vérifier 1 <= start <= end <= limit.", Value: "10"},
+			},
+		},
+		{
+			name:          "empty_rules_list",
+			body:          `{"limit": 15, "rules": [], "int2": 5, "str1": "fizz", "str2": "buzz"}`,
+			expectedError: "ValidationInt1Error",
+			expectedFields: []model.ValidationError{
+				{Field: "int1", Message: "Parameter int1 must be 1 or more."},
+			},
+		},
+		{
+			name:          "rules_conflict_and_cursor",
+			body:          `{"limit": 15, "rules": [{"divisor": 3, "word": "fizz"}], "int1": 3, "cursor": "bogus"}`,
+			expectedError: "ValidationRulesConflictError",
+			expectedFields: []model.ValidationError{
+				{Field: "rules", Message: "Parameter rules cannot be combined with int1, int2, str1 or str2."},
+				{Field: "cursor", Message: "Parameter cursor is invalid for this range.", Value: "bogus"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			controller := NewFizzBuzzController(mocks.NewMockIFizzBuzzService(t), newTestConfig())
+
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			errors.HTTPErrorHandler(controller.GenerateFizzBuzz(c), c)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedError, response.Error)
+			assert.Equal(t, tt.expectedFields, response.Errors)
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_ConfiguredBounds(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{MaxLimit: 50, MinStringLength: 2, MaxStringLength: 5, MinDivisor: 2, MaxDivisor: 20}}
+
+	tests := []struct {
+		name           string
+		body           string
+		acceptLanguage string
+		expectedError  string
+		expectedFields []model.ValidationError
+	}{
+		{
+			name:          "legacy_fields",
+			body:          `{"int1": 1, "int2": 21, "limit": 51, "str1": "fizzbuzz", "str2": "b"}`,
+			expectedError: "ValidationInt1Error",
+			expectedFields: []model.ValidationError{
+				{Field: "int1", Message: "Parameter int1 must be between 2 and 20.", Value: "1"},
+				{Field: "int2", Message: "Parameter int2 must be between 2 and 20.", Value: "21"},
+				{Field: "str1", Message: "Parameter str1 must be between 2 and 5 characters of valid UTF-8 text, without NUL.", Value: "fizzbuzz"},
+				{Field: "str2", Message: "Parameter str2 must be between 2 and 5 characters of valid UTF-8 text, without NUL.", Value: "b"},
+				{Field: "limit", Message: "Parameter limit must be between 1 and 50.", Value: "51"},
+			},
+		},
+		{
+			name:           "rules_translated",
+			body:           `{"limit": 100, "rules": [{"divisor": 30, "word": "fizzbuzz"}], "page_size": 60}`,
+			acceptLanguage: "fr",
+			expectedError:  "ValidationRuleDivisorError",
+			expectedFields: []model.ValidationError{
+				{Field: "rules[0].divisor", Message: "Le diviseur de chaque règle doit être entre 2 et 20.", Value: "30"},
+				{Field: "rules[0].word", Message: "Le mot de chaque règle doit contenir entre 2 et 5 caractères de texte UTF-8 valide, sans NUL.", Value: "fizzbuzz"},
+				{Field: "page_size", Message: "Le paramètre page_size doit être entre 1 et 50, et est requis si l'intervalle dépasse 50 valeurs.", Value: "60"},
+			},
+		},
+		{
+			name:          "page_required",
+			body:          `{"int1": 3, "int2": 5, "limit": 100, "str1": "fizz", "str2": "buzz", "start": 1}`,
+			expectedError: "ValidationPageSizeError",
+			expectedFields: []model.ValidationError{
+				{Field: "page_size", Message: "Parameter page_size must be between 1 and 50, and is required when the range exceeds 50 values."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewRequestValidator(cfg)
+			controller := NewFizzBuzzController(mocks.NewMockIFizzBuzzService(t), cfg)
+
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			errors.HTTPErrorHandler(controller.GenerateFizzBuzz(c), c)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedError, response.Error)
+			assert.Equal(t, tt.expectedFields, response.Errors)
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_Locale(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		expectedLocale string
+	}{
+		{name: "none", acceptLanguage: "fr", expectedLocale: ""},
+		{name: "exact", locale: "ar", expectedLocale: "ar"},
+		{name: "region_truncated", locale: "fr-BE", expectedLocale: "fr"},
+		{name: "auto", locale: "auto", acceptLanguage: "de-AT;q=0.5, es-MX", expectedLocale: "es"},
+		{name: "auto_default", locale: "auto", acceptLanguage: "ja", expectedLocale: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIFizzBuzzService(t)
+			controller := NewFizzBuzzController(mockService, newTestConfig())
+
+			request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 3, Str1: "fizz", Str2: "buzz", Locale: tt.locale}
+			expected := request
+			expected.Locale = tt.expectedLocale
+			mockService.EXPECT().GenerateFizzBuzz(mock.Anything, expected).Return(&model.FizzBuzzResponse{}, nil).Once()
+
+			requestBody, _ := json.Marshal(request)
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			assert.NoError(t, controller.GenerateFizzBuzz(c))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_InvalidOutputOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedError string
+		expectedField model.ValidationError
+	}{
+		{
+			name:          "unknown_locale",
+			body:          `{"int1": 3, "int2": 5, "limit": 15, "str1": "fizz", "str2": "buzz", "locale": "tlh"}`,
+			expectedError: "ValidationLocaleError",
+			expectedField: model.ValidationError{Field: "locale", Message: errors.ValidationLocaleError.Message("en"), Value: "tlh"},
+		},
+		{
+			name:          "unknown_number_format",
+			body:          `{"int1": 3, "int2": 5, "limit": 15, "str1": "fizz", "str2": "buzz", "number_format": "base64"}`,
+			expectedError: "ValidationNumberFormatError",
+			expectedField: model.ValidationError{Field: "number_format", Message: errors.ValidationNumberFormatError.Message("en"), Value: "base64"},
+		},
+		{
+			name:          "roman_above_3999",
+			body:          `{"int1": 3, "int2": 5, "limit": 5000, "str1": "fizz", "str2": "buzz", "start": 3990, "page_size": 20, "number_format": "roman"}`,
+			expectedError: "ValidationRomanNumeralError",
+			expectedField: model.ValidationError{Field: "number_format", Message: errors.ValidationRomanNumeralError.Message("en"), Value: "roman"},
+		},
+		{
+			name:          "locale_with_number_format",
+			body:          `{"int1": 3, "int2": 5, "limit": 15, "str1": "fizz", "str2": "buzz", "locale": "ar", "number_format": "hex"}`,
+			expectedError: "ValidationNumberFormatLocaleError",
+			expectedField: model.ValidationError{Field: "number_format", Message: errors.ValidationNumberFormatLocaleError.Message("en"), Value: "hex"},
+		},
+		{
+			name:          "words_without_locale",
+			body:          `{"int1": 3, "int2": 5, "limit": 15, "str1": "fizz", "str2": "buzz", "localize_words": true}`,
+			expectedError: "ValidationLocaleError",
+			expectedField: model.ValidationError{Field: "localize_words", Message: errors.ValidationLocaleError.Message("en"), Value: "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			controller := NewFizzBuzzController(mocks.NewMockIFizzBuzzService(t), newTestConfig())
+
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			errors.HTTPErrorHandler(controller.GenerateFizzBuzz(c), c)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.expectedError, response.Error)
+			assert.Equal(t, []model.ValidationError{tt.expectedField}, response.Errors)
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_Rules(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Limit: 7,
+		Rules: []model.Rule{
+			{Divisor: 3, Word: "Fizz"},
+			{Divisor: 5, Word: "Buzz"},
+			{Divisor: 7, Word: "Bazz"},
+		},
+	}
+
+	expectedResponse := &model.FizzBuzzResponse{
+		Result: []string{"1", "2", "Fizz", "4", "Buzz", "Fizz", "Bazz"},
+		Count:  7,
+	}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(expectedResponse, nil).Once()
+
+	requestBody := `{"limit":7,"rules":[{"divisor":3,"word":"Fizz"},{"divisor":5,"word":"Buzz"},{"divisor":7,"word":"Bazz"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBufferString(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GenerateFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.FizzBuzzResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse.Result, response.Result)
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_Paginated(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	// Limit above the single-response cap is fine as long as each page stays under it
+	request := model.FizzBuzzRequest{
+		Int1:     3,
+		Int2:     5,
+		Limit:    10000000,
+		Str1:     "fizz",
+		Str2:     "buzz",
+		Start:    9000000,
+		End:      9000100,
+		PageSize: 2,
+	}
+
+	expectedResponse := &model.FizzBuzzResponse{
+		Result:     []string{"fizzbuzz", "9000001"},
+		Count:      2,
+		Start:      9000000,
+		Total:      101,
+		NextCursor: service.EncodeCursor(9000002),
+	}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(expectedResponse, nil).Once()
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GenerateFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.FizzBuzzResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, *expectedResponse, response)
+}
+
 func TestFizzBuzzController_GenerateFizzBuzz_ServiceError(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIFizzBuzzService(t)
-	controller := NewFizzBuzzController(mockService)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
 
 	request := model.FizzBuzzRequest{
 		Int1:  3,
@@ -243,9 +695,9 @@ func TestFizzBuzzController_GenerateFizzBuzz_ServiceError(t *testing.T) {
 }
 
 func TestFizzBuzzController_GenerateFizzBuzz_InvalidJSON(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIFizzBuzzService(t)
-	controller := NewFizzBuzzController(mockService)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
 
 	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBuffer([]byte("invalid json")))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -258,12 +710,380 @@ func TestFizzBuzzController_GenerateFizzBuzz_InvalidJSON(t *testing.T) {
 	he, ok := err.(*echo.HTTPError)
 	assert.True(t, ok)
 	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	// Only the message of the binder is sent as cause
+	errors.HTTPErrorHandler(err, c)
+	var response model.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, map[string]string{"cause": "Syntax error: offset=1, error=invalid character 'i' looking for beginning of value"}, response.Details)
+}
+
+func TestFizzBuzzController_GenerateFizzBuzzBatch(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	valid := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 3, Str1: "fizz", Str2: "buzz"}
+	invalid := model.FizzBuzzRequest{Int1: 0, Int2: 5, Limit: 3, Str1: "fizz", Str2: "buzz"}
+	failing := model.FizzBuzzRequest{Int1: 2, Int2: 4, Limit: 2, Str1: "foo", Str2: "bar"}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, valid).Return(&model.FizzBuzzResponse{
+		Result: []string{"1", "2", "fizz"},
+		Count:  3,
+	}, nil).Once()
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, failing).Return(nil, assert.AnError).Once()
+
+	requestBody, _ := json.Marshal([]model.FizzBuzzRequest{valid, invalid, failing})
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz/batch", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "fr")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GenerateFizzBuzzBatch(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.FizzBuzzBatchResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 2, response.Failed)
+	assert.Len(t, response.Items, 3)
+
+	assert.Equal(t, 0, response.Items[0].Index)
+	assert.Equal(t, []string{"1", "2", "fizz"}, response.Items[0].Response.Result)
+	assert.Nil(t, response.Items[0].Error)
+
+	assert.Equal(t, 1, response.Items[1].Index)
+	assert.Nil(t, response.Items[1].Response)
+	assert.Equal(t, "ValidationInt1Error", response.Items[1].Error.Error)
+	assert.Equal(t, "Le paramètre int1 doit être supérieur ou égal à 1.", response.Items[1].Error.Message)
+	assert.Equal(t, []model.ValidationError{{Field: "int1", Message: "Le paramètre int1 doit être supérieur ou égal à 1."}}, response.Items[1].Error.Errors)
+
+	assert.Equal(t, 2, response.Items[2].Index)
+	assert.Equal(t, "ServiceError", response.Items[2].Error.Error)
+}
+
+func TestFizzBuzzController_GenerateFizzBuzzBatch_InvalidBatch(t *testing.T) {
+	tooManyValues := make([]model.FizzBuzzRequest, 101)
+	for i := range tooManyValues {
+		tooManyValues[i] = model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 10000, Str1: "fizz", Str2: "buzz"}
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty", body: `[]`},
+		{name: "not_an_array", body: `{"int1": 3}`},
+		{name: "too_many_requests", body: func() string {
+			body, _ := json.Marshal(make([]model.FizzBuzzRequest, 1001))
+			return string(body)
+		}()},
+		{name: "too_many_values", body: func() string {
+			body, _ := json.Marshal(tooManyValues)
+			return string(body)
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIFizzBuzzService(t)
+			controller := NewFizzBuzzController(mockService, newTestConfig())
+
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz/batch", bytes.NewBufferString(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.GenerateFizzBuzzBatch(c)
+
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestFizzBuzzController_GetFizzBuzz(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 5,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(&model.FizzBuzzResponse{
+		Result: []string{"1", "2", "fizz", "4", "buzz"},
+		Count:  5,
+		Start:  1,
+		Total:  5,
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?int1=3&int2=5&limit=5&str1=fizz&str2=buzz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, fizzBuzzETag(request, formatJSON), rec.Header().Get("ETag"))
+	assert.Equal(t, "public, no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+
+	var response model.FizzBuzzResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "fizz", "4", "buzz"}, response.Result)
+}
+
+func TestFizzBuzzController_GetFizzBuzz_AutoLocale(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	etags := make(map[string]string)
+	for _, acceptLanguage := range []string{"fr", "ar-EG"} {
+		request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 2, Str1: "fizz", Str2: "buzz", Locale: acceptLanguage[:2]}
+		mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(&model.FizzBuzzResponse{}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?int1=3&int2=5&limit=2&str1=fizz&str2=buzz&locale=auto", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, controller.GetFizzBuzz(c))
+		assert.Equal(t, []string{echo.HeaderAccept, "Accept-Language"}, rec.Header().Values(echo.HeaderVary))
+		etags[acceptLanguage] = rec.Header().Get(headerETag)
+	}
+
+	assert.NotEqual(t, etags["fr"], etags["ar-EG"])
+}
+
+func TestFizzBuzzController_GetFizzBuzz_NumberFormatCase(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	// The service gets the format in lower case, whatever the case of the query
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 2, Str1: "fizz", Str2: "buzz", NumberFormat: "hex"}
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(&model.FizzBuzzResponse{}, nil).Twice()
+
+	var etags []string
+	for _, numberFormat := range []string{"hex", "HEX"} {
+		req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?int1=3&int2=5&limit=2&str1=fizz&str2=buzz&number_format="+numberFormat, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, controller.GetFizzBuzz(c))
+		etags = append(etags, rec.Header().Get(headerETag))
+	}
+
+	assert.Equal(t, fizzBuzzETag(request, formatJSON), etags[0])
+	assert.Equal(t, etags[0], etags[1])
+}
+
+func TestFizzBuzzController_GetFizzBuzz_NotModified(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 5,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	// No generation, but the hit must still be counted
+	mockService.EXPECT().RecordRequest(mock.Anything, request).Return().Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?int1=3&int2=5&limit=5&str1=fizz&str2=buzz", nil)
+	req.Header.Set("If-None-Match", fizzBuzzETag(request, formatJSON))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, fizzBuzzETag(request, formatJSON), rec.Header().Get("ETag"))
+}
+
+func TestFizzBuzzController_GetFizzBuzz_StaleETag(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 5,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(&model.FizzBuzzResponse{
+		Result: []string{"1", "2", "fizz", "4", "buzz"},
+		Count:  5,
+	}, nil).Once()
+
+	// ETag of the JSON representation does not validate the CSV one
+	req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?int1=3&int2=5&limit=5&str1=fizz&str2=buzz&format=csv", nil)
+	req.Header.Set("If-None-Match", fizzBuzzETag(request, formatJSON))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, fizzBuzzETag(request, formatCSV), rec.Header().Get("ETag"))
+}
+
+func TestFizzBuzzController_GetFizzBuzz_ValidationError(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "int1_zero", query: "int1=0&int2=5&limit=5&str1=fizz&str2=buzz"},
+		// Unlike JSON bodies, query strings can carry invalid UTF-8
+		{name: "str1_invalid_utf8", query: "int1=3&int2=5&limit=5&str1=fi%FFzz&str2=buzz"},
+		{name: "str2_nul", query: "int1=3&int2=5&limit=5&str1=fizz&str2=bu%00zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIFizzBuzzService(t)
+			controller := NewFizzBuzzController(mockService, newTestConfig())
+
+			req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.GetFizzBuzz(c)
+
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestFizzBuzzController_StreamFizzBuzz(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	// Above the buffered endpoint cap, which streaming must accept
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 20000,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockService.EXPECT().StreamFizzBuzz(mock.Anything, request, mock.Anything).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error {
+			for _, value := range []string{"1", "2", "fizz"} {
+				if err := emit(value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Once()
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz/stream", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.StreamFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "\"1\"\n\"2\"\n\"fizz\"\n", rec.Body.String())
+}
+
+func TestFizzBuzzController_StreamFizzBuzz_IgnoresPagination(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	// page_size is above MAX_LIMIT, which only matters to the buffered endpoints
+	body := `{"int1": 3, "int2": 5, "limit": 20000, "str1": "fizz", "str2": "buzz", "start": 2, "end": 3, "page_size": 20000, "cursor": "bogus"}`
+	expected := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 20000, Str1: "fizz", Str2: "buzz", Start: 2, End: 3}
+
+	mockService.EXPECT().StreamFizzBuzz(mock.Anything, expected, mock.Anything).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error {
+			for _, value := range []string{"2", "fizz"} {
+				if err := emit(value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz/stream", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.StreamFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "\"2\"\n\"fizz\"\n", rec.Body.String())
+}
+
+func TestFizzBuzzController_StreamFizzBuzz_ValidationError(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 100000001,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz/stream", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.StreamFizzBuzz(c)
+
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
 }
 
 func TestFizzBuzzController_HealthCheck(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIFizzBuzzService(t)
-	controller := NewFizzBuzzController(mockService)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+	"github.com/julietteengel/fizzbuzz-api/internal/service"
+)
+
+// responseFormat is a representation of a FizzBuzz result, selectable with ?format=
+type responseFormat string
+
+const (
+	formatJSON   responseFormat = "json"
+	formatText   responseFormat = "text"
+	formatCSV    responseFormat = "csv"
+	formatNDJSON responseFormat = "ndjson"
+	formatXML    responseFormat = "xml"
+
+	mimeTextCSV = "text/csv"
+
+	headerTotalCount = "X-Total-Count"
+	headerNextCursor = "X-Next-Cursor"
+)
+
+// supportedFormats lists formats in server preference order, used to resolve wildcards in Accept
+var supportedFormats = []struct {
+	format   responseFormat
+	mimeType string
+}{
+	{formatJSON, echo.MIMEApplicationJSON},
+	{formatXML, echo.MIMEApplicationXML},
+	{formatNDJSON, mimeApplicationNDJSON},
+	{formatText, echo.MIMETextPlain},
+	{formatCSV, mimeTextCSV},
+}
+
+// localeAuto asks for the locale matching the Accept-Language header
+const localeAuto = "auto"
+
+// negotiateLocale returns the locale of service.Locales() matching requested, a language tag or localeAuto,
+// with the lookup that translates error messages. It returns false when requested matches no locale.
+func negotiateLocale(ctx echo.Context, requested string) (string, bool) {
+	switch {
+	case requested == "":
+		return "", true
+	case strings.EqualFold(requested, localeAuto):
+		return errors.LookupLanguage(ctx.Request().Header.Get("Accept-Language"), service.Locales(), service.DefaultLocale), true
+	}
+
+	locale := errors.LookupLanguage(requested, service.Locales(), "")
+	return locale, locale != ""
+}
+
+// localizeRequest replaces the locale of a validated request with the locale it matches, and writes
+// its number format in lower case so that equivalent requests share an ETag
+func localizeRequest(ctx echo.Context, request model.FizzBuzzRequest) model.FizzBuzzRequest {
+	request.Locale, _ = negotiateLocale(ctx, request.Locale)
+	request.NumberFormat = strings.ToLower(request.NumberFormat)
+	return request
+}
+
+// negotiateFormat picks the response format from the format query parameter, falling back
+// to the Accept header. It returns false when none of the requested types is supported.
+func negotiateFormat(ctx echo.Context) (responseFormat, bool) {
+	if format := ctx.QueryParam("format"); format != "" {
+		for _, supported := range supportedFormats {
+			if string(supported.format) == strings.ToLower(format) {
+				return supported.format, true
+			}
+		}
+		return "", false
+	}
+
+	accept := ctx.Request().Header.Get(echo.HeaderAccept)
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON, true
+	}
+
+	best, bestQuality := responseFormat(""), 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mimeType, quality := parseMediaRange(mediaRange)
+		if quality <= bestQuality {
+			continue
+		}
+		if format, ok := matchMediaRange(mimeType); ok {
+			best, bestQuality = format, quality
+		}
+	}
+
+	return best, best != ""
+}
+
+// parseMediaRange splits "text/csv;q=0.5" into its lower-cased type and quality (1 when absent)
+func parseMediaRange(mediaRange string) (string, float64) {
+	parts := strings.Split(mediaRange, ";")
+	mimeType := strings.ToLower(strings.TrimSpace(parts[0]))
+	quality := 1.0
+
+	for _, param := range parts[1:] {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(name, "q") {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+	}
+
+	return mimeType, quality
+}
+
+func matchMediaRange(mimeType string) (responseFormat, bool) {
+	for _, supported := range supportedFormats {
+		if mimeType == "*/*" || mimeType == supported.mimeType {
+			return supported.format, true
+		}
+		if prefix, ok := strings.CutSuffix(mimeType, "/*"); ok && strings.HasPrefix(supported.mimeType, prefix+"/") {
+			return supported.format, true
+		}
+	}
+	return "", false
+}
+
+// writeFizzBuzzResponse renders the response in the negotiated format.
+func writeFizzBuzzResponse(ctx echo.Context, format responseFormat, response *model.FizzBuzzResponse) error {
+	var body bytes.Buffer
+
+	switch format {
+	case formatXML:
+		return ctx.XML(http.StatusOK, response)
+
+	case formatText:
+		setPaginationHeaders(ctx, response)
+		for _, value := range response.Result {
+			body.WriteString(value)
+			body.WriteByte('\n')
+		}
+		return ctx.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, body.Bytes())
+
+	case formatCSV:
+		setPaginationHeaders(ctx, response)
+		writer := csv.NewWriter(&body)
+		_ = writer.Write([]string{"index", "value"})
+		for i, value := range response.Result {
+			_ = writer.Write([]string{strconv.Itoa(response.Start + i), value})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		return ctx.Blob(http.StatusOK, mimeTextCSV+"; charset=UTF-8", body.Bytes())
+
+	case formatNDJSON:
+		setPaginationHeaders(ctx, response)
+		encoder := json.NewEncoder(&body)
+		for _, value := range response.Result {
+			if err := encoder.Encode(value); err != nil {
+				return err
+			}
+		}
+		return ctx.Blob(http.StatusOK, mimeApplicationNDJSON, body.Bytes())
+
+	default:
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// setPaginationHeaders exposes total and next_cursor for line-based formats, whose body cannot carry them
+func setPaginationHeaders(ctx echo.Context, response *model.FizzBuzzResponse) {
+	header := ctx.Response().Header()
+	header.Set(headerTotalCount, strconv.Itoa(response.Total))
+	if response.NextCursor != "" {
+		header.Set(headerNextCursor, response.NextCursor)
+	}
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/mocks"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	e := newTestEcho()
+
+	tests := []struct {
+		name     string
+		query    string
+		accept   string
+		expected responseFormat
+		ok       bool
+	}{
+		{name: "no_preference", expected: formatJSON, ok: true},
+		{name: "wildcard", accept: "*/*", expected: formatJSON, ok: true},
+		{name: "browser_default", accept: "text/html,application/xhtml+xml,*/*;q=0.8", expected: formatJSON, ok: true},
+		{name: "csv", accept: "text/csv", expected: formatCSV, ok: true},
+		{name: "plain_text", accept: "text/plain", expected: formatText, ok: true},
+		{name: "ndjson", accept: "application/x-ndjson", expected: formatNDJSON, ok: true},
+		{name: "xml", accept: "application/xml", expected: formatXML, ok: true},
+		{name: "quality_values", accept: "application/json;q=0.5, text/csv;q=0.9", expected: formatCSV, ok: true},
+		{name: "case_insensitive", accept: "Text/CSV", expected: formatCSV, ok: true},
+		{name: "type_wildcard", accept: "text/*", expected: formatText, ok: true},
+		{name: "query_overrides_accept", query: "xml", accept: "text/csv", expected: formatXML, ok: true},
+		{name: "unsupported_accept", accept: "image/png", ok: false},
+		{name: "excluded_with_zero_quality", accept: "application/json;q=0", ok: false},
+		{name: "unsupported_query", query: "yaml", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/fizzbuzz"
+			if tt.query != "" {
+				target += "?format=" + tt.query
+			}
+			req := httptest.NewRequest(http.MethodPost, target, nil)
+			if tt.accept != "" {
+				req.Header.Set(echo.HeaderAccept, tt.accept)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			format, ok := negotiateFormat(c)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_Formats(t *testing.T) {
+	request := model.FizzBuzzRequest{
+		Int1:     3,
+		Int2:     5,
+		Limit:    15,
+		Str1:     "fizz",
+		Str2:     "buzz",
+		Start:    2,
+		PageSize: 3,
+	}
+
+	serviceResponse := &model.FizzBuzzResponse{
+		Result:     []string{"2", "fizz", "4"},
+		Count:      3,
+		Start:      2,
+		Total:      14,
+		NextCursor: "bjo1",
+	}
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "plain_text",
+			accept:              "text/plain",
+			expectedContentType: echo.MIMETextPlainCharsetUTF8,
+			expectedBody:        "2\nfizz\n4\n",
+		},
+		{
+			name:                "csv",
+			accept:              "text/csv",
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedBody:        "index,value\n2,2\n3,fizz\n4,4\n",
+		},
+		{
+			name:                "ndjson",
+			accept:              "application/x-ndjson",
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "\"2\"\n\"fizz\"\n\"4\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIFizzBuzzService(t)
+			controller := NewFizzBuzzController(mockService, newTestConfig())
+
+			mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(serviceResponse, nil).Once()
+
+			requestBody, _ := json.Marshal(request)
+			req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.GenerateFizzBuzz(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+			assert.Equal(t, "14", rec.Header().Get(headerTotalCount))
+			assert.Equal(t, "bjo1", rec.Header().Get(headerNextCursor))
+		})
+	}
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_XML(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 3,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockService.EXPECT().GenerateFizzBuzz(mock.Anything, request).Return(&model.FizzBuzzResponse{
+		Result: []string{"1", "2", "fizz"},
+		Count:  3,
+		Start:  1,
+		Total:  3,
+	}, nil).Once()
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz?format=xml", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GenerateFizzBuzz(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationXMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<fizzbuzz><result><value>1</value><value>2</value><value>fizz</value></result><count>3</count>")
+
+	var response model.FizzBuzzResponse
+	err = xml.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "fizz"}, response.Result)
+}
+
+func TestFizzBuzzController_GenerateFizzBuzz_NotAcceptable(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIFizzBuzzService(t)
+	controller := NewFizzBuzzController(mockService, newTestConfig())
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 15,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/fizzbuzz", bytes.NewBuffer(requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, "application/pdf")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GenerateFizzBuzz(c)
+
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotAcceptable, he.Code)
+}
@@ -1,14 +1,32 @@
 package controller
 
 import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 
 	"github.com/julietteengel/fizzbuzz-api/common/errors"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
 	"github.com/julietteengel/fizzbuzz-api/internal/service"
 )
 
+const (
+	// defaultTopN is the page size of GET /stats/top when n is omitted
+	defaultTopN = 10
+	// maxTopN caps the page size of GET /stats/top
+	maxTopN = 100
+)
+
 type StatsController struct {
 	service service.IStatsService
 }
@@ -21,22 +39,399 @@ func NewStatsController(service service.IStatsService) *StatsController {
 
 // GetStats returns statistics about the most frequently requested parameters.
 // @Summary Get FizzBuzz statistics
-// @Description Returns statistics about the most frequently requested FizzBuzz parameters
+// @Description Returns statistics about the most frequently requested FizzBuzz parameters.
+// @Description With window, or since and/or until, only hits in that time range are counted. Ranges are
+// @Description rounded to the configured bucket granularity (minute, hour or day) and limited by bucket retention.
 // @Tags stats
 // @Produce json
+// @Param window query string false "Count hits of the last duration, e.g. 30m, 1h, 24h"
+// @Param since query string false "Count hits from this RFC 3339 timestamp"
+// @Param until query string false "Count hits before this RFC 3339 timestamp (default: now)"
 // @Success 200 {object} model.StatsResponse
 // @Success 204 "No statistics available yet"
-// @Failure 500 {string} string "Service error message (translated)"
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
 // @Router /api/v1/stats [get]
 func (c *StatsController) GetStats(ctx echo.Context) error {
-	stats, err := c.service.GetMostFrequent(ctx.Request().Context())
+	since, until, windowed, ce := parseStatsTimeRange(ctx, time.Now())
+	if ce != nil {
+		return errors.WrapErrorHTTP(ctx, nil, *ce)
+	}
+
+	var stats *model.StatsResponse
+	var err error
+	if windowed {
+		stats, err = c.service.GetMostFrequentBetween(ctx.Request().Context(), since, until)
+	} else {
+		stats, err = c.service.GetMostFrequent(ctx.Request().Context())
+	}
 	if err != nil {
 		return errors.WrapErrorHTTP(ctx, err, errors.StatsRetrievalError)
 	}
 
 	if stats == nil {
-		return errors.WrapErrorHTTP(ctx, nil, errors.StatsNotFoundError)
+		return ctx.NoContent(errors.StatsNotFoundError.HttpErrorCode)
 	}
 
 	return ctx.JSON(http.StatusOK, stats)
-}
\ No newline at end of file
+}
+
+// GetTopStats returns the most frequently requested parameters, most hits first.
+// @Summary Get top FizzBuzz statistics
+// @Description Returns a page of the most frequently requested FizzBuzz parameters.
+// @Description Entries with the same hit count are ordered by first request time, so pages are stable across calls and storage backends.
+// @Tags stats
+// @Produce json
+// @Param n query int false "Number of entries (1-100)" default(10)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {object} model.StatsTopResponse
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/stats/top [get]
+func (c *StatsController) GetTopStats(ctx echo.Context) error {
+	n, offset := defaultTopN, 0
+
+	if value := ctx.QueryParam("n"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopN {
+			return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStatsTopNError)
+		}
+		n = parsed
+	}
+
+	if value := ctx.QueryParam("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStatsOffsetError)
+		}
+		offset = parsed
+	}
+
+	top, err := c.service.GetTop(ctx.Request().Context(), n, offset)
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsRetrievalError)
+	}
+
+	return ctx.JSON(http.StatusOK, top)
+}
+
+// GetStoreMetrics describes the stats store, including how many entries the in-memory store evicted.
+// @Summary Get stats store metrics
+// @Description Returns the number of distinct requests stored and, with memory storage, its bounds and how many
+// @Description entries were evicted (max_entries reached) or expired (entry_ttl without requests) since startup.
+// @Tags stats
+// @Produce json
+// @Success 200 {object} model.StatsStoreMetrics
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/stats/store [get]
+func (c *StatsController) GetStoreMetrics(ctx echo.Context) error {
+	metrics, err := c.service.GetStoreMetrics(ctx.Request().Context())
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsRetrievalError)
+	}
+
+	return ctx.JSON(http.StatusOK, metrics)
+}
+
+// ExportStats returns every stats entry, to be imported in another environment.
+// @Summary Export FizzBuzz statistics
+// @Description Returns every stats entry (parameter set, all-time hit count, first and last request times), most hits first.
+// @Description Hits per time bucket are not exported. Requires the admin token.
+// @Tags admin
+// @Produce json,text/csv
+// @Security AdminToken
+// @Param format query string false "Export format" Enums(json, csv) default(json)
+// @Success 200 {object} model.StatsExport
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 401 {object} model.ErrorResponse "Missing or invalid admin token (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/admin/stats/export [get]
+func (c *StatsController) ExportStats(ctx echo.Context) error {
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		format = service.StatsTransferJSON
+	}
+	if format != service.StatsTransferJSON && format != service.StatsTransferCSV {
+		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStatsFormatError)
+	}
+
+	export, err := c.service.ExportStats(ctx.Request().Context())
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsRetrievalError)
+	}
+
+	var body bytes.Buffer
+	if err := service.EncodeStatsExport(&body, format, export); err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsRetrievalError)
+	}
+
+	mimeType := echo.MIMEApplicationJSONCharsetUTF8
+	if format == service.StatsTransferCSV {
+		mimeType = mimeTextCSV + "; charset=UTF-8"
+	}
+	filename := fmt.Sprintf("fizzbuzz-stats-%s.%s", export.ExportedAt.Format("20060102T150405Z"), format)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return ctx.Blob(http.StatusOK, mimeType, body.Bytes())
+}
+
+// ImportStats adds exported stats entries to the store.
+// @Summary Import FizzBuzz statistics
+// @Description Imports a document produced by the export endpoint. The hit counts of parameter sets already stored are
+// @Description summed, keeping the earliest first request time. Imported hits only count in all-time stats, not in
+// @Description windowed ones. The whole document is validated first: when an entry is invalid nothing is imported.
+// @Description The format is read from the format query parameter, then from Content-Type (text/csv or JSON).
+// @Tags admin
+// @Accept json,text/csv
+// @Produce json
+// @Security AdminToken
+// @Param format query string false "Import format, overrides Content-Type" Enums(json, csv)
+// @Param request body model.StatsExport true "Exported statistics"
+// @Success 200 {object} model.StatsImportResult
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse "Missing or invalid admin token (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/admin/stats/import [post]
+func (c *StatsController) ImportStats(ctx echo.Context) error {
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		format = service.StatsTransferJSON
+		if strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderContentType), mimeTextCSV) {
+			format = service.StatsTransferCSV
+		}
+	}
+	if format != service.StatsTransferJSON && format != service.StatsTransferCSV {
+		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStatsFormatError)
+	}
+
+	entries, err := service.DecodeStatsImport(ctx.Request().Body, format)
+	if err == nil {
+		var result *model.StatsImportResult
+		if result, err = c.service.ImportStats(ctx.Request().Context(), entries); err == nil {
+			return ctx.JSON(http.StatusOK, result)
+		}
+	}
+
+	var importErr *service.StatsImportError
+	if !stderrors.As(err, &importErr) {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsImportFailedError)
+	}
+
+	field := "document"
+	if importErr.Entry > 0 {
+		field = fmt.Sprintf("entry %d", importErr.Entry) // Counted from 1, CSV header excluded
+	}
+	return errors.WrapValidationErrorHTTP(ctx, errors.ValidationStatsImportError, []model.ValidationError{{Field: field, Message: importErr.Reason}})
+}
+
+// DeleteStats deletes every stats entry, or the entry of one parameter set.
+// @Summary Delete FizzBuzz statistics
+// @Description With all=true alone, deletes every stats entry and its hits per time bucket. With the parameters of a
+// @Description FizzBuzz request, in the query string (legacy form) or as a JSON body (either form), only deletes the
+// @Description entry of that parameter set. Any other parameter is rejected.
+// @Description Deletions cannot be undone and are recorded in the audit log. Requires the admin token.
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param all query bool false "Delete every entry, without any other parameter"
+// @Param int1 query int false "First divisor"
+// @Param int2 query int false "Second divisor"
+// @Param limit query int false "Upper limit of the sequence"
+// @Param str1 query string false "Replacement for multiples of int1"
+// @Param str2 query string false "Replacement for multiples of int2"
+// @Param request body model.FizzBuzzRequest false "Parameter set to delete, to pass rules"
+// @Success 200 {object} model.StatsDeleteResult
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 401 {object} model.ErrorResponse "Missing or invalid admin token (translated)"
+// @Failure 404 {object} model.ErrorResponse "No entry for these parameters (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/stats [delete]
+func (c *StatsController) DeleteStats(ctx echo.Context) error {
+	request, all, err := bindStatsDeletion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		result, err := c.service.ResetStats(ctx.Request().Context())
+		if err != nil {
+			return errors.WrapErrorHTTP(ctx, err, errors.StatsDeletionError)
+		}
+
+		auditStatsDeletion(ctx, "stats.reset", nil, result)
+		return ctx.JSON(http.StatusOK, result)
+	}
+
+	if err := validateStatsDeletion(request); err != nil {
+		return validationErrorHTTP(ctx, err)
+	}
+
+	result, err := c.service.DeleteStatsRequest(ctx.Request().Context(), request)
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsDeletionError)
+	}
+
+	auditStatsDeletion(ctx, "stats.delete_request", model.NewFizzBuzzRequest(request.EffectiveRules(), request.Limit), result)
+	if result.Entries == 0 {
+		return errors.WrapErrorHTTP(ctx, nil, errors.StatsEntryNotFoundError)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// statsDeletionParams are the parameters naming the parameter set to delete, in the query string or the JSON body
+var statsDeletionParams = map[string]bool{"int1": true, "int2": true, "limit": true, "str1": true, "str2": true, "rules": true}
+
+// bindStatsDeletion reads what DELETE /stats deletes: every entry with all=true alone, or else the parameter
+// set of the query string and body. Any other parameter is rejected, so that a typo cannot turn a deletion
+// into a reset or match another parameter set.
+func bindStatsDeletion(ctx echo.Context) (model.FizzBuzzRequest, bool, error) {
+	var request model.FizzBuzzRequest
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return request, false, bindError(ctx, err)
+	}
+	ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+	if err := ctx.Bind(&request); err != nil {
+		return request, false, bindError(ctx, err)
+	}
+
+	// Bind only accepts a JSON object as body: its keys are the parameters it holds
+	var bodyParams map[string]json.RawMessage
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &bodyParams); err != nil {
+			return request, false, bindError(ctx, err)
+		}
+	}
+
+	query := ctx.QueryParams()
+	all := query.Has("all")
+	queryNames := make([]string, 0, len(query))
+	for name := range query {
+		queryNames = append(queryNames, name)
+	}
+	bodyNames := make([]string, 0, len(bodyParams))
+	for name := range bodyParams {
+		bodyNames = append(bodyNames, name)
+	}
+	sort.Strings(queryNames)
+	sort.Strings(bodyNames)
+
+	var invalid fieldErrors
+	for _, name := range queryNames {
+		reset := name == "all" && query.Get(name) == "true" && len(query) == 1 && len(bodyParams) == 0
+		if !statsDeletionParams[name] && !reset {
+			invalid.add(name, query.Get(name), errors.ValidationStatsDeletionParamError)
+		}
+	}
+	for _, name := range bodyNames {
+		if !statsDeletionParams[name] {
+			invalid.add(name, nil, errors.ValidationStatsDeletionParamError)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return request, false, validationErrorHTTP(ctx, invalid)
+	}
+	return request, all, nil
+}
+
+// validateStatsDeletion only checks that request names a parameter set: entries recorded before the
+// configured word and divisor bounds changed stay deletable. Any limit of a streamed request may have been recorded.
+func validateStatsDeletion(request model.FizzBuzzRequest) error {
+	var invalid fieldErrors
+	if request.Limit < 1 || request.Limit > maxStreamLimit {
+		invalid.add("limit", request.Limit, errors.ValidationStatsDeletionLimitError)
+	}
+	if len(request.Rules) > 0 && request.HasLegacyParams() {
+		invalid.add("rules", nil, errors.ValidationRulesConflictError)
+	}
+
+	if len(invalid) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// DeleteStatsEntry deletes one stats entry by ID.
+// @Summary Delete a FizzBuzz statistics entry
+// @Description Deletes the stats entry with the id returned by GET /api/v1/stats and /api/v1/stats/top, and its hits
+// @Description per time bucket. Deletions cannot be undone and are recorded in the audit log. Requires the admin token.
+// @Tags stats
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Entry ID"
+// @Success 200 {object} model.StatsDeleteResult
+// @Failure 400 {object} model.ErrorResponse "Validation error message (translated)"
+// @Failure 401 {object} model.ErrorResponse "Missing or invalid admin token (translated)"
+// @Failure 404 {object} model.ErrorResponse "No entry with this ID (translated)"
+// @Failure 500 {object} model.ErrorResponse "Service error message (translated)"
+// @Router /api/v1/stats/{id} [delete]
+func (c *StatsController) DeleteStatsEntry(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		return errors.WrapErrorHTTP(ctx, nil, errors.ValidationStatsIDError)
+	}
+
+	result, err := c.service.DeleteStatsEntry(ctx.Request().Context(), uint(id))
+	if err != nil {
+		return errors.WrapErrorHTTP(ctx, err, errors.StatsDeletionError)
+	}
+
+	auditStatsDeletion(ctx, "stats.delete_entry", id, result)
+	if result.Entries == 0 {
+		return errors.WrapErrorHTTP(ctx, nil, errors.StatsEntryNotFoundError)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// auditStatsDeletion writes a deletion to the audit log: what was asked, by whom, and what it removed.
+// Deletions that matched nothing are logged as well.
+func auditStatsDeletion(ctx echo.Context, action string, target interface{}, result *model.StatsDeleteResult) {
+	entry := log.JSON{
+		"audit":      action,
+		"request_id": ctx.Response().Header().Get(echo.HeaderXRequestID),
+		"remote_ip":  ctx.RealIP(),
+		"entries":    result.Entries,
+		"hits":       result.Hits,
+	}
+	if target != nil {
+		entry["target"] = target
+	}
+	log.Infoj(entry)
+}
+
+// parseStatsTimeRange reads the window, since and until query parameters. windowed is false
+// when none is set, meaning all-time stats; otherwise until defaults to now and since to the zero time.
+func parseStatsTimeRange(ctx echo.Context, now time.Time) (since, until time.Time, windowed bool, ce *errors.ControllerError) {
+	window, sinceParam, untilParam := ctx.QueryParam("window"), ctx.QueryParam("since"), ctx.QueryParam("until")
+
+	if window != "" {
+		duration, err := time.ParseDuration(window)
+		if err != nil || duration <= 0 || sinceParam != "" || untilParam != "" {
+			return since, until, false, &errors.ValidationStatsWindowError
+		}
+		return now.Add(-duration), now, true, nil
+	}
+
+	if sinceParam == "" && untilParam == "" {
+		return since, until, false, nil
+	}
+
+	until = now
+	var err error
+	if sinceParam != "" {
+		if since, err = time.Parse(time.RFC3339, sinceParam); err != nil {
+			return since, until, false, &errors.ValidationStatsTimeRangeError
+		}
+	}
+	if untilParam != "" {
+		if until, err = time.Parse(time.RFC3339, untilParam); err != nil {
+			return since, until, false, &errors.ValidationStatsTimeRangeError
+		}
+	}
+	if !since.Before(until) {
+		return since, until, false, &errors.ValidationStatsTimeRangeError
+	}
+
+	return since, until, true, nil
+}
@@ -1,21 +1,26 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
 	"github.com/julietteengel/fizzbuzz-api/internal/mocks"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
+	"github.com/julietteengel/fizzbuzz-api/internal/service"
 )
 
 func TestStatsController_GetStats_Success(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIStatsService(t)
 	controller := NewStatsController(mockService)
 
@@ -49,7 +54,7 @@ func TestStatsController_GetStats_Success(t *testing.T) {
 }
 
 func TestStatsController_GetStats_NoStats(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIStatsService(t)
 	controller := NewStatsController(mockService)
 
@@ -67,7 +72,7 @@ func TestStatsController_GetStats_NoStats(t *testing.T) {
 }
 
 func TestStatsController_GetStats_ServiceError(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIStatsService(t)
 	controller := NewStatsController(mockService)
 
@@ -86,7 +91,7 @@ func TestStatsController_GetStats_ServiceError(t *testing.T) {
 }
 
 func TestStatsController_GetStats_ContextPassing(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIStatsService(t)
 	controller := NewStatsController(mockService)
 
@@ -120,7 +125,7 @@ func TestStatsController_GetStats_ContextPassing(t *testing.T) {
 }
 
 func TestStatsController_GetStats_ZeroHitCount(t *testing.T) {
-	e := echo.New()
+	e := newTestEcho()
 	mockService := mocks.NewMockIStatsService(t)
 	controller := NewStatsController(mockService)
 
@@ -152,4 +157,699 @@ func TestStatsController_GetStats_ZeroHitCount(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, statsWithZeroHits.Request, response.Request)
 	assert.Equal(t, int64(0), response.HitCount)
-}
\ No newline at end of file
+}
+
+func TestStatsController_GetTopStats(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	expected := &model.StatsTopResponse{
+		Items: []model.StatsResponse{
+			{
+				Request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+				HitCount: 42,
+			},
+		},
+		N:      5,
+		Offset: 20,
+		Total:  21,
+	}
+
+	mockService.EXPECT().GetTop(mock.Anything, 5, 20).Return(expected, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/top?n=5&offset=20", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetTopStats(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.StatsTopResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, &response)
+}
+
+func TestStatsController_GetTopStats_Defaults(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	mockService.EXPECT().GetTop(mock.Anything, 10, 0).Return(&model.StatsTopResponse{
+		Items: []model.StatsResponse{},
+		N:     10,
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/top", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetTopStats(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestStatsController_GetTopStats_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "n_zero", query: "n=0"},
+		{name: "n_too_high", query: "n=101"},
+		{name: "n_not_a_number", query: "n=ten"},
+		{name: "offset_negative", query: "offset=-1"},
+		{name: "offset_not_a_number", query: "offset=first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/top?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.GetTopStats(c)
+
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestStatsController_GetTopStats_ServiceError(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	mockService.EXPECT().GetTop(mock.Anything, 10, 0).Return(nil, assert.AnError).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/top", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetTopStats(c)
+
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestStatsController_GetStats_Window(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	expected := &model.StatsResponse{
+		Request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		HitCount: 7,
+	}
+
+	mockService.EXPECT().GetMostFrequentBetween(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, since, until time.Time) (*model.StatsResponse, error) {
+			assert.Equal(t, time.Hour, until.Sub(since))
+			assert.WithinDuration(t, time.Now(), until, time.Minute)
+			return expected, nil
+		}).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats?window=1h", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetStats(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestStatsController_GetStats_SinceUntil(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	since := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().GetMostFrequentBetween(mock.Anything, since, until).Return(nil, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats?since=2025-03-01T10:00:00Z&until=2025-03-02T10:00:00Z", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetStats(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestStatsController_GetStats_TimeRangeValidationErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "window_not_a_duration", query: "window=hour"},
+		{name: "window_negative", query: "window=-1h"},
+		{name: "window_with_since", query: "window=1h&since=2025-03-01T10:00:00Z"},
+		{name: "since_not_rfc3339", query: "since=2025-03-01"},
+		{name: "until_not_rfc3339", query: "until=yesterday"},
+		{name: "since_after_until", query: "since=2025-03-02T10:00:00Z&until=2025-03-01T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.GetStats(c)
+
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestStatsController_GetStoreMetrics(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	expected := &model.StatsStoreMetrics{
+		Storage:        "memory",
+		Entries:        10000,
+		MaxEntries:     10000,
+		EvictionPolicy: "lru",
+		EntryTTL:       "24h0m0s",
+		Evictions:      42,
+		Expirations:    7,
+	}
+	mockService.EXPECT().GetStoreMetrics(mock.Anything).Return(expected, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/store", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetStoreMetrics(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.StatsStoreMetrics
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, &response)
+}
+
+func TestStatsController_GetStoreMetrics_ServiceError(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	mockService.EXPECT().GetStoreMetrics(mock.Anything).Return(nil, assert.AnError).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/store", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.GetStoreMetrics(c)
+
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestStatsController_ExportStats(t *testing.T) {
+	export := &model.StatsExport{
+		ExportedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Entries: []model.StatsExportEntry{{
+			Request:   model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+			HitCount:  42,
+			CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedContentType string
+		expectedFilename    string
+		expectedBody        string
+	}{
+		{
+			name:                "json_by_default",
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedFilename:    "fizzbuzz-stats-20250301T120000Z.json",
+			expectedBody:        `"hit_count": 42`,
+		},
+		{
+			name:                "csv",
+			query:               "?format=csv",
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedFilename:    "fizzbuzz-stats-20250301T120000Z.csv",
+			expectedBody:        "int1,int2,limit,str1,str2,rules,hit_count,created_at,updated_at\n3,5,100,fizz,buzz,,42,2025-01-01T00:00:00Z,2025-02-01T00:00:00Z\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			mockService.EXPECT().ExportStats(mock.Anything).Return(export, nil).Once()
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/stats/export"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.ExportStats(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), tt.expectedFilename)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestStatsController_ExportStats_Errors(t *testing.T) {
+	t.Run("unsupported_format", func(t *testing.T) {
+		e := newTestEcho()
+		controller := NewStatsController(mocks.NewMockIStatsService(t))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/stats/export?format=xml", nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := controller.ExportStats(c)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+	})
+
+	t.Run("service_error", func(t *testing.T) {
+		e := newTestEcho()
+		mockService := mocks.NewMockIStatsService(t)
+		controller := NewStatsController(mockService)
+
+		mockService.EXPECT().ExportStats(mock.Anything).Return(nil, assert.AnError).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/stats/export", nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := controller.ExportStats(c)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+	})
+}
+
+func TestStatsController_ImportStats(t *testing.T) {
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"entries": [{"request": {"int1": 3, "int2": 5, "limit": 100, "str1": "fizz", "str2": "buzz"}, "hit_count": 4}]}`,
+		},
+		{
+			name:        "csv_content_type",
+			contentType: "text/csv; charset=UTF-8",
+			body:        "int1,int2,limit,str1,str2,hit_count\n3,5,100,fizz,buzz,4\n",
+		},
+		{
+			name:        "csv_format_parameter",
+			query:       "?format=csv",
+			contentType: "application/octet-stream",
+			body:        "int1,int2,limit,str1,str2,hit_count\n3,5,100,fizz,buzz,4\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			expected := &model.StatsImportResult{Entries: 1, Hits: 4}
+			mockService.EXPECT().ImportStats(mock.Anything, []model.StatsExportEntry{{Request: fizzBuzz, HitCount: 4}}).Return(expected, nil).Once()
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/stats/import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.ImportStats(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response model.StatsImportResult
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, expected, &response)
+		})
+	}
+}
+
+func TestStatsController_ImportStats_InvalidDocument(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		serviceError  error // Returned by ImportStats when the document decodes
+		expectedField string
+	}{
+		{name: "malformed_json", body: `{"entries": [`, expectedField: "document"},
+		{name: "invalid_entry", body: `{"entries": [{"request": {"limit": 10}, "hit_count": 1}]}`, serviceError: &service.StatsImportError{Entry: 1, Reason: "divisors must be 1 or more"}, expectedField: "entry 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			if tt.serviceError != nil {
+				mockService.EXPECT().ImportStats(mock.Anything, mock.Anything).Return(nil, tt.serviceError).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/stats/import", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.ImportStats(c)
+			errors.HTTPErrorHandler(err, c)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "ValidationStatsImportError", response.Error)
+			if assert.Len(t, response.Errors, 1) {
+				assert.Equal(t, tt.expectedField, response.Errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestStatsController_ImportStats_ServiceError(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	mockService.EXPECT().ImportStats(mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/stats/import", strings.NewReader(`{"entries": []}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := controller.ImportStats(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestStatsController_DeleteStats_Reset(t *testing.T) {
+	e := newTestEcho()
+	mockService := mocks.NewMockIStatsService(t)
+	controller := NewStatsController(mockService)
+
+	expected := &model.StatsDeleteResult{Entries: 3, Hits: 12}
+	mockService.EXPECT().ResetStats(mock.Anything).Return(expected, nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/stats?all=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := controller.DeleteStats(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response model.StatsDeleteResult
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, expected, &response)
+}
+
+func TestStatsController_DeleteStats_ByParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		expected model.FizzBuzzRequest
+	}{
+		{
+			name:     "query",
+			query:    "?int1=3&int2=5&limit=100&str1=fizz&str2=buzz",
+			expected: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		},
+		{
+			name:     "rules_body",
+			body:     `{"limit": 30, "rules": [{"divisor": 3, "word": "fizz"}, {"divisor": 5, "word": "buzz"}, {"divisor": 7, "word": "bazz"}]}`,
+			expected: model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}, {Divisor: 7, Word: "bazz"}}},
+		},
+		{
+			// Recorded before the word bounds were lowered
+			name:     "outside_configured_bounds",
+			query:    "?int1=3&int2=5&limit=100&str1=fizz&str2=" + strings.Repeat("b", 101),
+			expected: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: strings.Repeat("b", 101)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+
+			mockService.EXPECT().DeleteStatsRequest(mock.Anything, tt.expected).Return(&model.StatsDeleteResult{Entries: 1, Hits: 4}, nil).Once()
+
+			req := httptest.NewRequest(http.MethodDelete, "/stats"+tt.query, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := controller.DeleteStats(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestStatsController_DeleteStats_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		body         string
+		mockSetup    func(*mocks.MockIStatsService)
+		expectedCode int
+	}{
+		{name: "missing_limit", query: "?int1=3&int2=5&str1=fizz&str2=buzz", expectedCode: http.StatusBadRequest},
+		{name: "rules_and_legacy", body: `{"int1": 3, "limit": 100, "rules": [{"divisor": 2, "word": "even"}]}`, expectedCode: http.StatusBadRequest},
+		{
+			name:  "not_found",
+			query: "?int1=3&int2=5&limit=100&str1=fizz&str2=buzz",
+			mockSetup: func(mockService *mocks.MockIStatsService) {
+				mockService.EXPECT().DeleteStatsRequest(mock.Anything, mock.Anything).Return(&model.StatsDeleteResult{}, nil).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:  "service_error",
+			query: "?all=true",
+			mockSetup: func(mockService *mocks.MockIStatsService) {
+				mockService.EXPECT().ResetStats(mock.Anything).Return(nil, assert.AnError).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockService)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/stats"+tt.query, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := controller.DeleteStats(c)
+
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestStatsController_DeleteStats_RejectedParameters(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		expectedError  string
+		expectedFields []model.ValidationError
+	}{
+		{
+			// A reset must be asked for explicitly
+			name:           "empty",
+			expectedError:  "ValidationStatsDeletionLimitError",
+			expectedFields: []model.ValidationError{{Field: "limit", Message: errors.ValidationStatsDeletionLimitError.Message("en"), Value: "0"}},
+		},
+		{
+			name:           "zero_limit",
+			query:          "?limit=0",
+			expectedError:  "ValidationStatsDeletionLimitError",
+			expectedFields: []model.ValidationError{{Field: "limit", Message: errors.ValidationStatsDeletionLimitError.Message("en"), Value: "0"}},
+		},
+		{
+			name:           "misspelled",
+			query:          "?lmit=100",
+			expectedError:  "ValidationStatsDeletionParamError",
+			expectedFields: []model.ValidationError{{Field: "lmit", Message: errors.ValidationStatsDeletionParamError.Message("en"), Value: "100"}},
+		},
+		{
+			name:          "output_options",
+			query:         "?number_format=hex&locale=fr",
+			expectedError: "ValidationStatsDeletionParamError",
+			expectedFields: []model.ValidationError{
+				{Field: "locale", Message: errors.ValidationStatsDeletionParamError.Message("en"), Value: "fr"},
+				{Field: "number_format", Message: errors.ValidationStatsDeletionParamError.Message("en"), Value: "hex"},
+			},
+		},
+		{
+			name:           "body_range",
+			body:           `{"limit": 30, "rules": [{"divisor": 3, "word": "fizz"}], "start": 10}`,
+			expectedError:  "ValidationStatsDeletionParamError",
+			expectedFields: []model.ValidationError{{Field: "start", Message: errors.ValidationStatsDeletionParamError.Message("en")}},
+		},
+		{
+			name:           "all_false",
+			query:          "?all=false",
+			expectedError:  "ValidationStatsDeletionParamError",
+			expectedFields: []model.ValidationError{{Field: "all", Message: errors.ValidationStatsDeletionParamError.Message("en"), Value: "false"}},
+		},
+		{
+			// Deleting everything and one parameter set at once is ambiguous
+			name:           "all_with_parameters",
+			query:          "?all=true",
+			body:           `{"limit": 30, "rules": [{"divisor": 3, "word": "fizz"}]}`,
+			expectedError:  "ValidationStatsDeletionParamError",
+			expectedFields: []model.ValidationError{{Field: "all", Message: errors.ValidationStatsDeletionParamError.Message("en"), Value: "true"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			controller := NewStatsController(mocks.NewMockIStatsService(t))
+
+			req := httptest.NewRequest(http.MethodDelete, "/stats"+tt.query, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			errors.HTTPErrorHandler(controller.DeleteStats(c), c)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var response model.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedError, response.Error)
+			assert.Equal(t, tt.expectedFields, response.Errors)
+		})
+	}
+}
+
+func TestStatsController_DeleteStatsEntry(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		mockSetup    func(*mocks.MockIStatsService)
+		expectedCode int
+	}{
+		{
+			name: "deleted",
+			id:   "42",
+			mockSetup: func(mockService *mocks.MockIStatsService) {
+				mockService.EXPECT().DeleteStatsEntry(mock.Anything, uint(42)).Return(&model.StatsDeleteResult{Entries: 1, Hits: 7}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "not_found",
+			id:   "43",
+			mockSetup: func(mockService *mocks.MockIStatsService) {
+				mockService.EXPECT().DeleteStatsEntry(mock.Anything, uint(43)).Return(&model.StatsDeleteResult{}, nil).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{name: "zero", id: "0", expectedCode: http.StatusBadRequest},
+		{name: "not_a_number", id: "top", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEcho()
+			mockService := mocks.NewMockIStatsService(t)
+			controller := NewStatsController(mockService)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockService)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/stats/"+tt.id, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			err := controller.DeleteStatsEntry(c)
+
+			if tt.expectedCode == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, he.Code)
+		})
+	}
+}
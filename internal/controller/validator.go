@@ -0,0 +1,215 @@
+package controller
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+
+	"github.com/julietteengel/fizzbuzz-api/common/errors"
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// RequestValidator is the echo.Validator checking the `validate` tags of request structs.
+// Besides the tags of the validator package, the divisor, word and page_size tags check the bounds of cfg.
+type RequestValidator struct {
+	validate *validator.Validate
+}
+
+func NewRequestValidator(cfg *config.Config) *RequestValidator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	// Fields are reported by their JSON name, as the client sent them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	limits := newFizzBuzzLimits(cfg)
+	boundTags := map[string]validator.Func{
+		"divisor":   func(fl validator.FieldLevel) bool { return limits.validDivisor(int(fl.Field().Int())) },
+		"word":      func(fl validator.FieldLevel) bool { return limits.validWord(fl.Field().String()) },
+		"page_size": func(fl validator.FieldLevel) bool { return int(fl.Field().Int()) <= limits.maxLimit },
+	}
+	for tag, validateBound := range boundTags {
+		// Only fails for an empty tag or a nil function
+		if err := validate.RegisterValidation(tag, validateBound); err != nil {
+			panic(err)
+		}
+	}
+
+	return &RequestValidator{validate: validate}
+}
+
+func (v *RequestValidator) Validate(i interface{}) error {
+	return v.validate.Struct(i)
+}
+
+// fizzBuzzLimits holds the bounds of FizzBuzz requests set in config.AppConfig, and the errors reporting
+// the fields of model.FizzBuzzRequest with these bounds in their messages
+type fizzBuzzLimits struct {
+	maxLimit        int // Largest limit of a sequence returned in a single document, and largest page_size
+	minStringLength int
+	maxStringLength int
+	minDivisor      int
+	maxDivisor      int // 0 for no maximum
+
+	// errorsByField maps the struct fields of model.FizzBuzzRequest to their error.
+	// Limit is missing: its error depends on the endpoint.
+	errorsByField map[string]errors.ControllerError
+	limitError    errors.ControllerError // Limit of a sequence returned in a single document
+}
+
+func newFizzBuzzLimits(cfg *config.Config) fizzBuzzLimits {
+	maxBounds := map[string]interface{}{"Max": cfg.App.MaxLimit}
+	lengthBounds := map[string]interface{}{"Min": cfg.App.MinStringLength, "Max": cfg.App.MaxStringLength}
+	divisorBounds := map[string]interface{}{"Min": cfg.App.MinDivisor, "Max": cfg.App.MaxDivisor}
+
+	return fizzBuzzLimits{
+		maxLimit:        cfg.App.MaxLimit,
+		minStringLength: cfg.App.MinStringLength,
+		maxStringLength: cfg.App.MaxStringLength,
+		minDivisor:      cfg.App.MinDivisor,
+		maxDivisor:      cfg.App.MaxDivisor,
+		errorsByField: map[string]errors.ControllerError{
+			"Int1":          errors.ValidationInt1Error.With(divisorBounds),
+			"Int2":          errors.ValidationInt2Error.With(divisorBounds),
+			"Str1":          errors.ValidationStr1Error.With(lengthBounds),
+			"Str2":          errors.ValidationStr2Error.With(lengthBounds),
+			"Rules":         errors.ValidationRulesError,
+			"Divisor":       errors.ValidationRuleDivisorError.With(divisorBounds),
+			"Word":          errors.ValidationRuleWordError.With(lengthBounds),
+			"Start":         errors.ValidationRangeError,
+			"End":           errors.ValidationRangeError,
+			"PageSize":      errors.ValidationPageSizeError.With(maxBounds),
+			"LocalizeWords": errors.ValidationLocaleError,
+		},
+		limitError: errors.ValidationLimitError.With(maxBounds),
+	}
+}
+
+// tagError returns the error of a field rejected by its `validate` tag
+func (l fizzBuzzLimits) tagError(tagErr validator.FieldError, limitError errors.ControllerError) errors.ControllerError {
+	if tagErr.StructField() == "Limit" {
+		return limitError
+	}
+	if controllerErr, known := l.errorsByField[tagErr.StructField()]; known {
+		return controllerErr
+	}
+	return errors.InvalidRequestError
+}
+
+// validDivisor reports whether divisor is within the configured bounds
+func (l fizzBuzzLimits) validDivisor(divisor int) bool {
+	return divisor >= l.minDivisor && (l.maxDivisor == 0 || divisor <= l.maxDivisor)
+}
+
+// validWord reports whether word is text the database can store, valid UTF-8 without NUL,
+// and whether its length in characters is within the configured bounds
+func (l fizzBuzzLimits) validWord(word string) bool {
+	if !utf8.ValidString(word) || strings.ContainsRune(word, 0) {
+		return false
+	}
+	length := utf8.RuneCountInString(word)
+	return length >= l.minStringLength && length <= l.maxStringLength
+}
+
+// fieldError is an invalid field of a request and the ControllerError explaining it
+type fieldError struct {
+	field string // JSON name, e.g. rules[2].word
+	value string // Rejected value, empty when the field is missing
+	err   errors.ControllerError
+}
+
+// fieldErrors lists every invalid field of a request, in the order they were checked
+type fieldErrors []fieldError
+
+func (f fieldErrors) Error() string {
+	messages := make([]string, 0, len(f))
+	for _, fieldErr := range f {
+		messages = append(messages, fieldErr.field+": "+fieldErr.err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records an invalid field; a nil value leaves the rejected value out of the response
+func (f *fieldErrors) add(field string, value interface{}, err errors.ControllerError) {
+	fieldErr := fieldError{field: field, err: err}
+	if value != nil {
+		fieldErr.value = fmt.Sprint(value)
+	}
+	*f = append(*f, fieldErr)
+}
+
+// addTagError records a field rejected by its `validate` tag, reported with controllerErr
+func (f *fieldErrors) addTagError(tagErr validator.FieldError, controllerErr errors.ControllerError) {
+	// The namespace starts with the struct name: FizzBuzzRequest.rules[2].word
+	_, field, _ := strings.Cut(tagErr.Namespace(), ".")
+
+	var value interface{}
+	if !strings.HasPrefix(tagErr.Tag(), "required") && tagErr.Kind() != reflect.Slice {
+		value = tagErr.Value()
+	}
+	f.add(field, value, controllerErr)
+}
+
+// has reports whether any of fields is invalid
+func (f fieldErrors) has(fields ...string) bool {
+	for _, fieldErr := range f {
+		for _, field := range fields {
+			if fieldErr.field == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// translate returns the invalid fields with their messages in the language of the client
+func (f fieldErrors) translate(ctx echo.Context) []model.ValidationError {
+	translated := make([]model.ValidationError, 0, len(f))
+	for _, fieldErr := range f {
+		translated = append(translated, model.ValidationError{
+			Field:   fieldErr.field,
+			Message: errors.Translate(ctx, fieldErr.err),
+			Value:   fieldErr.value,
+		})
+	}
+	return translated
+}
+
+// validationErrorHTTP returns the error of a request rejected by validateFizzBuzzRequest or validateBufferedRequest:
+// every invalid field is listed, and the error code is the one of the first field
+func validationErrorHTTP(ctx echo.Context, err error) error {
+	var invalid fieldErrors
+	if !stderrors.As(err, &invalid) {
+		return errors.WrapErrorHTTP(ctx, err, errors.ServiceError)
+	}
+	return errors.WrapValidationErrorHTTP(ctx, invalid[0].err, invalid.translate(ctx))
+}
+
+// validationErrorResponse is validationErrorHTTP for the items of a batch
+func validationErrorResponse(ctx echo.Context, err error) *model.ValidationErrorResponse {
+	var invalid fieldErrors
+	if !stderrors.As(err, &invalid) {
+		log.Errorf("Error %s: %v", errors.ServiceError.Name, err)
+		return &model.ValidationErrorResponse{
+			Error:   errors.ServiceError.Name,
+			Message: errors.Translate(ctx, errors.ServiceError),
+		}
+	}
+	return &model.ValidationErrorResponse{
+		Error:   invalid[0].err.Name,
+		Message: errors.Translate(ctx, invalid[0].err),
+		Errors:  invalid.translate(ctx),
+	}
+}
@@ -1,35 +1,81 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	"github.com/julietteengel/fizzbuzz-api/internal/config"
-	"github.com/julietteengel/fizzbuzz-api/internal/model"
 )
 
-func NewGormDB(cfg *config.Config) *gorm.DB {
+// sqlitePragmas makes other processes (backups, sqlite3 shell) wait for locks instead of failing,
+// and lets them read while the server writes
+const sqlitePragmas = "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+// NewGormDB connects to the database selected by cfg and, unless cfg.Database.AutoMigrate is off,
+// applies the pending migrations. It returns nil in memory mode.
+func NewGormDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := Open(cfg)
+	if err != nil || db == nil {
+		return nil, err
+	}
+
+	if !cfg.Database.AutoMigrate {
+		log.Println("Automatic migration disabled, run \"migrate up\" to update the schema")
+		return db, nil
+	}
+
+	log.Println("Running database migrations...")
+	if err := MigrateUp(db); err != nil {
+		return nil, fmt.Errorf("could not migrate database: %w", err)
+	}
+
+	log.Println("Database connection and migration successful")
+	return db, nil
+}
+
+// Open connects to the database selected by cfg without migrating it. It returns nil in memory mode.
+func Open(cfg *config.Config) (*gorm.DB, error) {
 	log.Printf("Database config: StatsStorage=%s, URL=%s", cfg.Database.StatsStorage, cfg.Database.URL)
-	
-	if cfg.Database.StatsStorage == "memory" {
+
+	var db *gorm.DB
+	var err error
+	switch cfg.Database.StatsStorage {
+	case "memory":
 		log.Println("Using memory storage, skipping database connection")
-		return nil
+		return nil, nil
+
+	case "sqlite":
+		log.Printf("Opening SQLite database %s...", cfg.Database.SQLitePath)
+		db, err = OpenSQLite(cfg.Database.SQLitePath)
+
+	default:
+		log.Println("Connecting to PostgreSQL...")
+		db, err = gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{})
 	}
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	return db, nil
+}
 
-	log.Println("Connecting to PostgreSQL...")
-	db, err := gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{})
+// OpenSQLite opens (creating it if needed) the SQLite database at path, ":memory:" for a throwaway one.
+func OpenSQLite(path string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(path+sqlitePragmas), &gorm.Config{})
 	if err != nil {
-		log.Fatalf("Could not connect to database: %v", err)
+		return nil, err
 	}
 
-	log.Println("Running database migration...")
-	err = db.AutoMigrate(&model.StatsEntry{})
+	// SQLite allows a single writer: one connection serializes writes in the process instead of
+	// failing them with "database is locked", and keeps a ":memory:" database shared by all queries
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Could not migrate database: %v", err)
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(1)
 
-	log.Println("Database connection and migration successful")
-	return db
-}
\ No newline at end of file
+	return db, nil
+}
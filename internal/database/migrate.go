@@ -0,0 +1,296 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// migrationFiles holds one directory of migrations per dialect (postgres, sqlite). Each migration is
+// a pair of files <version>_<name>.up.sql and <version>_<name>.down.sql, the down file reverting the up one.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockID identifies the PostgreSQL advisory lock that keeps two instances from migrating at once
+const migrationLockID = 7_246_010
+
+// beforeUp runs, in the transaction of a migration and before its up file, the changes that depend on the
+// current schema: SQLite cannot add a column only when it is missing in plain SQL
+var beforeUp = map[int]func(tx *gorm.DB) error{
+	3: addMissingRulesColumn,
+	4: addRulesHashColumn,
+}
+
+// addMissingRulesColumn adds the rules column to stats_entries tables created by the AutoMigrate of versions
+// predating rules, which 0001_create_stats_entries kept as they were
+func addMissingRulesColumn(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn("stats_entries", "rules") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE stats_entries ADD COLUMN rules TEXT NOT NULL DEFAULT ''`).Error
+}
+
+// addRulesHashColumn adds the rules_hash column to stats_entries and fills it for the stored rules, before
+// 0004_index_stats_rules_hash puts it in idx_params. SQLite has no SHA-256 function: rows are hashed here.
+func addRulesHashColumn(tx *gorm.DB) error {
+	// Tables created by AutoMigrate after rules_hash existed already have it
+	if !tx.Migrator().HasColumn("stats_entries", "rules_hash") {
+		if err := tx.Exec(`ALTER TABLE stats_entries ADD COLUMN rules_hash VARCHAR(64) NOT NULL DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+
+	var entries []model.StatsEntry
+	if err := tx.Select("id", "rules").Where("rules <> ''").Find(&entries).Error; err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err := tx.Model(&model.StatsEntry{}).Where("id = ?", entry.ID).Update("rules_hash", model.HashRules(entry.Rules)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Migration is one versioned, reversible schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration is applied. Migrations applied by a newer binary, whose
+// files are unknown to this one, are listed with Unknown set.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+// schemaMigration is a row of schema_migrations: one per applied migration
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrateUp applies every pending migration in version order, each in its own transaction.
+func MigrateUp(db *gorm.DB) error {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	if err := createSchemaMigrations(db); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			applied, err := lockMigrations(tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := applied[migration.Version]; ok {
+				return nil
+			}
+
+			if prepare, ok := beforeUp[migration.Version]; ok {
+				if err := prepare(tx); err != nil {
+					return fmt.Errorf("apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+				}
+			}
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return fmt.Errorf("apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateDown reverts the steps most recently applied migrations, newest first.
+func MigrateDown(db *gorm.DB, steps int) error {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	if err := createSchemaMigrations(db); err != nil {
+		return err
+	}
+
+	for i := 0; i < steps; i++ {
+		reverted := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if _, err := lockMigrations(tx); err != nil {
+				return err
+			}
+
+			var last schemaMigration
+			result := tx.Order("version DESC").Limit(1).Find(&last)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			migration, ok := byVersion[last.Version]
+			if !ok {
+				return fmt.Errorf("migration %04d_%s is applied but unknown to this binary: revert it with the binary that applied it", last.Version, last.Name)
+			}
+
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return fmt.Errorf("revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+			reverted = true
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return err
+		}
+		if !reverted {
+			log.Println("No migration left to revert")
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetMigrationStatus lists the known migrations in version order, then the applied unknown ones.
+func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := createSchemaMigrations(db); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range rows {
+		if _, unknown := applied[row.Version]; unknown {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, MigrationStatus{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+		}
+	}
+	return statuses, nil
+}
+
+func createSchemaMigrations(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT       PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP    NOT NULL
+	)`).Error
+}
+
+// lockMigrations serializes migrations run by several instances until the end of tx, then returns
+// the versions already applied. SQLite needs no lock: it allows a single writer.
+func lockMigrations(tx *gorm.DB) (map[int]struct{}, error) {
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var versions []int
+	if err := tx.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]struct{}, len(versions))
+	for _, version := range versions {
+		applied[version] = struct{}{}
+	}
+	return applied, nil
+}
+
+// loadMigrations reads the migrations of dialect, sorted by version
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	files, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database %q", dialect)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range files {
+		match := migrationFileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s: expected <version>_<name>.up.sql or .down.sql", file.Name())
+		}
+		version, _ := strconv.Atoi(match[1]) // \d+ always parses
+		contents, err := fs.ReadFile(migrationFiles, path.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
@@ -0,0 +1,205 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := OpenSQLite(":memory:")
+	require.NoError(t, err)
+	return db
+}
+
+func appliedVersions(t *testing.T, db *gorm.DB) []int {
+	t.Helper()
+	statuses, err := GetMigrationStatus(db)
+	require.NoError(t, err)
+
+	versions := []int{}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func TestMigrations_UpDownStatus(t *testing.T) {
+	db := newTestDB(t)
+
+	statuses, err := GetMigrationStatus(db)
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+	assert.Equal(t, "create_stats_entries", statuses[0].Name)
+	assert.Nil(t, statuses[0].AppliedAt)
+
+	// Up is idempotent
+	require.NoError(t, MigrateUp(db))
+	require.NoError(t, MigrateUp(db))
+	assert.Equal(t, []int{1, 2, 3, 4}, appliedVersions(t, db))
+	assert.True(t, db.Migrator().HasTable(&model.StatsBucket{}))
+
+	// Down reverts the latest migrations only
+	require.NoError(t, MigrateDown(db, 3))
+	assert.Equal(t, []int{1}, appliedVersions(t, db))
+	assert.False(t, db.Migrator().HasTable(&model.StatsBucket{}))
+	assert.True(t, db.Migrator().HasTable(&model.StatsEntry{}))
+
+	// Reverting more migrations than applied stops at the first one
+	require.NoError(t, MigrateDown(db, 5))
+	assert.Empty(t, appliedVersions(t, db))
+	assert.False(t, db.Migrator().HasTable(&model.StatsEntry{}))
+
+	require.NoError(t, MigrateUp(db))
+	assert.Equal(t, []int{1, 2, 3, 4}, appliedVersions(t, db))
+}
+
+func TestMigrations_MatchModels(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, MigrateUp(db))
+
+	// The SQL schema accepts the rows the repositories write
+	entry := model.StatsEntry{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 1}
+	require.NoError(t, db.Create(&entry).Error)
+	require.NoError(t, db.Create(&model.StatsBucket{EntryID: entry.ID, BucketStart: entry.CreatedAt, HitCount: 1}).Error)
+
+	// and enforces the unique parameter set
+	duplicate := model.StatsEntry{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 1}
+	assert.Error(t, db.Create(&duplicate).Error)
+}
+
+func TestMigrations_AdoptAutoMigratedSchema(t *testing.T) {
+	db := newTestDB(t)
+
+	// A database created by the former AutoMigrate, with data
+	require.NoError(t, db.AutoMigrate(&model.StatsEntry{}, &model.StatsBucket{}))
+	require.NoError(t, db.Create(&model.StatsEntry{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 7}).Error)
+
+	require.NoError(t, MigrateUp(db))
+	assert.Equal(t, []int{1, 2, 3, 4}, appliedVersions(t, db))
+
+	var entry model.StatsEntry
+	require.NoError(t, db.First(&entry).Error)
+	assert.Equal(t, int64(7), entry.HitCount)
+}
+
+// baselineStatsEntry is the stats entry of the versions predating rules and versioned migrations
+type baselineStatsEntry struct {
+	ID        uint   `gorm:"primaryKey"`
+	Int1      int    `gorm:"not null;uniqueIndex:idx_params"`
+	Int2      int    `gorm:"not null;uniqueIndex:idx_params"`
+	Limit     int    `gorm:"not null;uniqueIndex:idx_params"`
+	Str1      string `gorm:"not null;size:100;uniqueIndex:idx_params"`
+	Str2      string `gorm:"not null;size:100;uniqueIndex:idx_params"`
+	HitCount  int64  `gorm:"not null;default:0;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineStatsEntry) TableName() string {
+	return "stats_entries"
+}
+
+func TestMigrations_AdoptBaselineSchema(t *testing.T) {
+	db := newTestDB(t)
+
+	// A database created by the AutoMigrate of the versions predating rules, with data
+	require.NoError(t, db.AutoMigrate(&baselineStatsEntry{}))
+	require.NoError(t, db.Create(&baselineStatsEntry{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 7}).Error)
+	require.False(t, db.Migrator().HasColumn("stats_entries", "rules"))
+
+	require.NoError(t, MigrateUp(db))
+	assert.Equal(t, []int{1, 2, 3, 4}, appliedVersions(t, db))
+
+	var entry model.StatsEntry
+	require.NoError(t, db.First(&entry).Error)
+	assert.Equal(t, int64(7), entry.HitCount)
+	assert.Empty(t, entry.Rules)
+
+	// The upsert of the repositories needs idx_params to include the hash of the rules
+	upsert := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "int1"}, {Name: "int2"}, {Name: "limit"}, {Name: "str1"}, {Name: "str2"}, {Name: "rules_hash"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"hit_count": gorm.Expr("stats_entries.hit_count + 1")}),
+	}
+	rules := `[{"divisor":7,"word":"bazz"}]`
+	require.NoError(t, db.Clauses(upsert).Create(&model.StatsEntry{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 1}).Error)
+	require.NoError(t, db.Clauses(upsert).Create(&model.StatsEntry{Limit: 100, Rules: rules, RulesHash: model.HashRules(rules), HitCount: 1}).Error)
+
+	var entries []model.StatsEntry
+	require.NoError(t, db.Order("id").Find(&entries).Error)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(8), entries[0].HitCount)
+	assert.Equal(t, `[{"divisor":7,"word":"bazz"}]`, entries[1].Rules)
+}
+
+func TestMigrations_HashStoredRules(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, MigrateUp(db))
+	require.NoError(t, MigrateDown(db, 1))
+	require.False(t, db.Migrator().HasColumn("stats_entries", "rules_hash"))
+
+	// Entries written with rules in idx_params
+	rules := []string{`[{"divisor":7,"word":"bazz"}]`, `[{"divisor":2,"word":"even"}]`}
+	for _, encoded := range rules {
+		require.NoError(t, db.Exec(`INSERT INTO stats_entries (int1, int2, "limit", str1, str2, rules, hit_count) VALUES (0, 0, 100, '', '', ?, 1)`, encoded).Error)
+	}
+	require.NoError(t, db.Exec(`INSERT INTO stats_entries (int1, int2, "limit", str1, str2, rules, hit_count) VALUES (3, 5, 100, 'fizz', 'buzz', '', 1)`).Error)
+
+	require.NoError(t, MigrateUp(db))
+
+	var entries []model.StatsEntry
+	require.NoError(t, db.Order("id").Find(&entries).Error)
+	require.Len(t, entries, 3)
+	assert.Equal(t, model.HashRules(rules[0]), entries[0].RulesHash)
+	assert.Equal(t, model.HashRules(rules[1]), entries[1].RulesHash)
+	assert.Empty(t, entries[2].RulesHash)
+
+	// idx_params now tells rule sets apart by their hash
+	duplicate := model.StatsEntry{Limit: 100, Rules: rules[0], RulesHash: model.HashRules(rules[0]), HitCount: 1}
+	assert.Error(t, db.Create(&duplicate).Error)
+}
+
+func TestMigrations_UnknownAppliedVersion(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, MigrateUp(db))
+
+	// Applied by a newer binary
+	require.NoError(t, db.Create(&schemaMigration{Version: 99, Name: "from_the_future"}).Error)
+
+	statuses, err := GetMigrationStatus(db)
+	require.NoError(t, err)
+	require.Len(t, statuses, 5)
+	assert.Equal(t, 99, statuses[4].Version)
+	assert.True(t, statuses[4].Unknown)
+
+	// It cannot be reverted without its down file
+	assert.Error(t, MigrateDown(db, 1))
+	assert.Equal(t, []int{1, 2, 3, 4, 99}, appliedVersions(t, db))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		t.Run(dialect, func(t *testing.T) {
+			migrations, err := loadMigrations(dialect)
+			require.NoError(t, err)
+			require.Len(t, migrations, 4)
+			for i, migration := range migrations {
+				assert.Equal(t, i+1, migration.Version)
+				assert.NotEmpty(t, migration.Up)
+				assert.NotEmpty(t, migration.Down)
+			}
+		})
+	}
+
+	_, err := loadMigrations("mysql")
+	assert.Error(t, err)
+}
@@ -0,0 +1,16 @@
+-- IF NOT EXISTS keeps a table created by GORM AutoMigrate before versioned migrations: 0003 brings it up to date
+CREATE TABLE IF NOT EXISTS stats_entries (
+    id         BIGSERIAL PRIMARY KEY,
+    int1       BIGINT       NOT NULL,
+    int2       BIGINT       NOT NULL,
+    "limit"    BIGINT       NOT NULL,
+    str1       VARCHAR(100) NOT NULL,
+    str2       VARCHAR(100) NOT NULL,
+    rules      TEXT         NOT NULL DEFAULT '',
+    hit_count  BIGINT       NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_params ON stats_entries (int1, int2, "limit", str1, str2, rules);
+CREATE INDEX IF NOT EXISTS idx_stats_entries_hit_count ON stats_entries (hit_count);
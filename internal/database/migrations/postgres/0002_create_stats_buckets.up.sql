@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS stats_buckets (
+    id           BIGSERIAL PRIMARY KEY,
+    entry_id     BIGINT      NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    hit_count    BIGINT      NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entry_bucket ON stats_buckets (entry_id, bucket_start);
+CREATE INDEX IF NOT EXISTS idx_stats_buckets_bucket_start ON stats_buckets (bucket_start);
@@ -0,0 +1,2 @@
+-- Nothing to revert: the schema is the one of 0001_create_stats_entries, whose down file drops it
+SELECT 1;
@@ -0,0 +1,4 @@
+-- stats_entries tables created by GORM AutoMigrate before rules existed were kept by 0001_create_stats_entries
+-- without the rules column, which is added beforehand (see beforeUp), nor rules in their idx_params index
+DROP INDEX IF EXISTS idx_params;
+CREATE UNIQUE INDEX idx_params ON stats_entries (int1, int2, "limit", str1, str2, rules);
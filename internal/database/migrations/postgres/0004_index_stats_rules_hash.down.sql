@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_params;
+CREATE UNIQUE INDEX idx_params ON stats_entries (int1, int2, "limit", str1, str2, rules);
+ALTER TABLE stats_entries DROP COLUMN IF EXISTS rules_hash;
@@ -0,0 +1,16 @@
+-- IF NOT EXISTS keeps a table created by GORM AutoMigrate before versioned migrations: 0003 brings it up to date
+CREATE TABLE IF NOT EXISTS stats_entries (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    int1       INTEGER NOT NULL,
+    int2       INTEGER NOT NULL,
+    "limit"    INTEGER NOT NULL,
+    str1       TEXT    NOT NULL,
+    str2       TEXT    NOT NULL,
+    rules      TEXT    NOT NULL DEFAULT '',
+    hit_count  INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_params ON stats_entries (int1, int2, "limit", str1, str2, rules);
+CREATE INDEX IF NOT EXISTS idx_stats_entries_hit_count ON stats_entries (hit_count);
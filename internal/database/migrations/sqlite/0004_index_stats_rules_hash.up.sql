@@ -0,0 +1,4 @@
+-- JSON-encoded rules can exceed the 2704 bytes of a PostgreSQL index row: idx_params indexes their SHA-256
+-- instead, in rules_hash, which is added and filled beforehand (see beforeUp)
+DROP INDEX IF EXISTS idx_params;
+CREATE UNIQUE INDEX idx_params ON stats_entries (int1, int2, "limit", str1, str2, rules_hash);
@@ -94,3 +94,85 @@ func (_c *MockIFizzBuzzService_GenerateFizzBuzz_Call) RunAndReturn(run func(ctx
 	_c.Call.Return(run)
 	return _c
 }
+
+// RecordRequest provides a mock function for the type MockIFizzBuzzService
+func (_mock *MockIFizzBuzzService) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) {
+	_mock.Called(ctx, request)
+	return
+}
+
+// MockIFizzBuzzService_RecordRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordRequest'
+type MockIFizzBuzzService_RecordRequest_Call struct {
+	*mock.Call
+}
+
+// RecordRequest is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockIFizzBuzzService_Expecter) RecordRequest(ctx interface{}, request interface{}) *MockIFizzBuzzService_RecordRequest_Call {
+	return &MockIFizzBuzzService_RecordRequest_Call{Call: _e.mock.On("RecordRequest", ctx, request)}
+}
+
+func (_c *MockIFizzBuzzService_RecordRequest_Call) Run(run func(ctx context.Context, request model.FizzBuzzRequest)) *MockIFizzBuzzService_RecordRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.FizzBuzzRequest))
+	})
+	return _c
+}
+
+func (_c *MockIFizzBuzzService_RecordRequest_Call) Return() *MockIFizzBuzzService_RecordRequest_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockIFizzBuzzService_RecordRequest_Call) RunAndReturn(run func(ctx context.Context, request model.FizzBuzzRequest)) *MockIFizzBuzzService_RecordRequest_Call {
+	_c.Run(run)
+	return _c
+}
+
+// StreamFizzBuzz provides a mock function for the type MockIFizzBuzzService
+func (_mock *MockIFizzBuzzService) StreamFizzBuzz(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error {
+	ret := _mock.Called(ctx, request, emit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamFizzBuzz")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.FizzBuzzRequest, func(value string) error) error); ok {
+		r0 = returnFunc(ctx, request, emit)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIFizzBuzzService_StreamFizzBuzz_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamFizzBuzz'
+type MockIFizzBuzzService_StreamFizzBuzz_Call struct {
+	*mock.Call
+}
+
+// StreamFizzBuzz is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - emit
+func (_e *MockIFizzBuzzService_Expecter) StreamFizzBuzz(ctx interface{}, request interface{}, emit interface{}) *MockIFizzBuzzService_StreamFizzBuzz_Call {
+	return &MockIFizzBuzzService_StreamFizzBuzz_Call{Call: _e.mock.On("StreamFizzBuzz", ctx, request, emit)}
+}
+
+func (_c *MockIFizzBuzzService_StreamFizzBuzz_Call) Run(run func(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error)) *MockIFizzBuzzService_StreamFizzBuzz_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.FizzBuzzRequest), args[2].(func(value string) error))
+	})
+	return _c
+}
+
+func (_c *MockIFizzBuzzService_StreamFizzBuzz_Call) Return(err error) *MockIFizzBuzzService_StreamFizzBuzz_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIFizzBuzzService_StreamFizzBuzz_Call) RunAndReturn(run func(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error) *MockIFizzBuzzService_StreamFizzBuzz_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,276 @@ func (_m *MockIStatsRepository) EXPECT() *MockIStatsRepository_Expecter {
 	return &MockIStatsRepository_Expecter{mock: &_m.Mock}
 }
 
+// Close provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStatsRepository_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockIStatsRepository_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockIStatsRepository_Expecter) Close() *MockIStatsRepository_Close_Call {
+	return &MockIStatsRepository_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockIStatsRepository_Close_Call) Run(run func()) *MockIStatsRepository_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_Close_Call) Return(err error) *MockIStatsRepository_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_Close_Call) RunAndReturn(run func() error) *MockIStatsRepository_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAll provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) DeleteAll(ctx context.Context) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAll")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_DeleteAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAll'
+type MockIStatsRepository_DeleteAll_Call struct {
+	*mock.Call
+}
+
+// DeleteAll is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsRepository_Expecter) DeleteAll(ctx interface{}) *MockIStatsRepository_DeleteAll_Call {
+	return &MockIStatsRepository_DeleteAll_Call{Call: _e.mock.On("DeleteAll", ctx)}
+}
+
+func (_c *MockIStatsRepository_DeleteAll_Call) Run(run func(ctx context.Context)) *MockIStatsRepository_DeleteAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteAll_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsRepository_DeleteAll_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteAll_Call) RunAndReturn(run func(ctx context.Context) (*model.StatsDeleteResult, error)) *MockIStatsRepository_DeleteAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteEntry provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) DeleteEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEntry")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_DeleteEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEntry'
+type MockIStatsRepository_DeleteEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteEntry is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockIStatsRepository_Expecter) DeleteEntry(ctx interface{}, id interface{}) *MockIStatsRepository_DeleteEntry_Call {
+	return &MockIStatsRepository_DeleteEntry_Call{Call: _e.mock.On("DeleteEntry", ctx, id)}
+}
+
+func (_c *MockIStatsRepository_DeleteEntry_Call) Run(run func(ctx context.Context, id uint)) *MockIStatsRepository_DeleteEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteEntry_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsRepository_DeleteEntry_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteEntry_Call) RunAndReturn(run func(ctx context.Context, id uint) (*model.StatsDeleteResult, error)) *MockIStatsRepository_DeleteEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRequest provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) DeleteRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRequest")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.FizzBuzzRequest) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.FizzBuzzRequest) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.FizzBuzzRequest) error); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_DeleteRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRequest'
+type MockIStatsRepository_DeleteRequest_Call struct {
+	*mock.Call
+}
+
+// DeleteRequest is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockIStatsRepository_Expecter) DeleteRequest(ctx interface{}, request interface{}) *MockIStatsRepository_DeleteRequest_Call {
+	return &MockIStatsRepository_DeleteRequest_Call{Call: _e.mock.On("DeleteRequest", ctx, request)}
+}
+
+func (_c *MockIStatsRepository_DeleteRequest_Call) Run(run func(ctx context.Context, request model.FizzBuzzRequest)) *MockIStatsRepository_DeleteRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.FizzBuzzRequest))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteRequest_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsRepository_DeleteRequest_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_DeleteRequest_Call) RunAndReturn(run func(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error)) *MockIStatsRepository_DeleteRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportEntries provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) ExportEntries(ctx context.Context) ([]model.StatsExportEntry, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportEntries")
+	}
+
+	var r0 []model.StatsExportEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]model.StatsExportEntry, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []model.StatsExportEntry); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StatsExportEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_ExportEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportEntries'
+type MockIStatsRepository_ExportEntries_Call struct {
+	*mock.Call
+}
+
+// ExportEntries is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsRepository_Expecter) ExportEntries(ctx interface{}) *MockIStatsRepository_ExportEntries_Call {
+	return &MockIStatsRepository_ExportEntries_Call{Call: _e.mock.On("ExportEntries", ctx)}
+}
+
+func (_c *MockIStatsRepository_ExportEntries_Call) Run(run func(ctx context.Context)) *MockIStatsRepository_ExportEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_ExportEntries_Call) Return(statsExportEntrys []model.StatsExportEntry, err error) *MockIStatsRepository_ExportEntries_Call {
+	_c.Call.Return(statsExportEntrys, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_ExportEntries_Call) RunAndReturn(run func(ctx context.Context) ([]model.StatsExportEntry, error)) *MockIStatsRepository_ExportEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMostFrequent provides a mock function for the type MockIStatsRepository
 func (_mock *MockIStatsRepository) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
 	ret := _mock.Called(ctx)
@@ -94,6 +365,241 @@ func (_c *MockIStatsRepository_GetMostFrequent_Call) RunAndReturn(run func(ctx c
 	return _c
 }
 
+// GetMostFrequentBetween provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) GetMostFrequentBetween(ctx context.Context, since time.Time, until time.Time) (*model.StatsResponse, error) {
+	ret := _mock.Called(ctx, since, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMostFrequentBetween")
+	}
+
+	var r0 *model.StatsResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (*model.StatsResponse, error)); ok {
+		return returnFunc(ctx, since, until)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *model.StatsResponse); ok {
+		r0 = returnFunc(ctx, since, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, since, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_GetMostFrequentBetween_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMostFrequentBetween'
+type MockIStatsRepository_GetMostFrequentBetween_Call struct {
+	*mock.Call
+}
+
+// GetMostFrequentBetween is a helper method to define mock.On call
+//   - ctx
+//   - since
+//   - until
+func (_e *MockIStatsRepository_Expecter) GetMostFrequentBetween(ctx interface{}, since interface{}, until interface{}) *MockIStatsRepository_GetMostFrequentBetween_Call {
+	return &MockIStatsRepository_GetMostFrequentBetween_Call{Call: _e.mock.On("GetMostFrequentBetween", ctx, since, until)}
+}
+
+func (_c *MockIStatsRepository_GetMostFrequentBetween_Call) Run(run func(ctx context.Context, since time.Time, until time.Time)) *MockIStatsRepository_GetMostFrequentBetween_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetMostFrequentBetween_Call) Return(statsResponse *model.StatsResponse, err error) *MockIStatsRepository_GetMostFrequentBetween_Call {
+	_c.Call.Return(statsResponse, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetMostFrequentBetween_Call) RunAndReturn(run func(ctx context.Context, since time.Time, until time.Time) (*model.StatsResponse, error)) *MockIStatsRepository_GetMostFrequentBetween_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStoreMetrics provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoreMetrics")
+	}
+
+	var r0 *model.StatsStoreMetrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.StatsStoreMetrics, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.StatsStoreMetrics); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsStoreMetrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_GetStoreMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStoreMetrics'
+type MockIStatsRepository_GetStoreMetrics_Call struct {
+	*mock.Call
+}
+
+// GetStoreMetrics is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsRepository_Expecter) GetStoreMetrics(ctx interface{}) *MockIStatsRepository_GetStoreMetrics_Call {
+	return &MockIStatsRepository_GetStoreMetrics_Call{Call: _e.mock.On("GetStoreMetrics", ctx)}
+}
+
+func (_c *MockIStatsRepository_GetStoreMetrics_Call) Run(run func(ctx context.Context)) *MockIStatsRepository_GetStoreMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetStoreMetrics_Call) Return(statsStoreMetrics *model.StatsStoreMetrics, err error) *MockIStatsRepository_GetStoreMetrics_Call {
+	_c.Call.Return(statsStoreMetrics, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetStoreMetrics_Call) RunAndReturn(run func(ctx context.Context) (*model.StatsStoreMetrics, error)) *MockIStatsRepository_GetStoreMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTop provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) GetTop(ctx context.Context, n int, offset int) ([]model.StatsResponse, int64, error) {
+	ret := _mock.Called(ctx, n, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTop")
+	}
+
+	var r0 []model.StatsResponse
+	var r1 int64
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]model.StatsResponse, int64, error)); ok {
+		return returnFunc(ctx, n, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []model.StatsResponse); ok {
+		r0 = returnFunc(ctx, n, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StatsResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int64); ok {
+		r1 = returnFunc(ctx, n, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = returnFunc(ctx, n, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockIStatsRepository_GetTop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTop'
+type MockIStatsRepository_GetTop_Call struct {
+	*mock.Call
+}
+
+// GetTop is a helper method to define mock.On call
+//   - ctx
+//   - n
+//   - offset
+func (_e *MockIStatsRepository_Expecter) GetTop(ctx interface{}, n interface{}, offset interface{}) *MockIStatsRepository_GetTop_Call {
+	return &MockIStatsRepository_GetTop_Call{Call: _e.mock.On("GetTop", ctx, n, offset)}
+}
+
+func (_c *MockIStatsRepository_GetTop_Call) Run(run func(ctx context.Context, n int, offset int)) *MockIStatsRepository_GetTop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetTop_Call) Return(statsResponses []model.StatsResponse, n int64, err error) *MockIStatsRepository_GetTop_Call {
+	_c.Call.Return(statsResponses, n, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_GetTop_Call) RunAndReturn(run func(ctx context.Context, n int, offset int) ([]model.StatsResponse, int64, error)) *MockIStatsRepository_GetTop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportEntries provides a mock function for the type MockIStatsRepository
+func (_mock *MockIStatsRepository) ImportEntries(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error) {
+	ret := _mock.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportEntries")
+	}
+
+	var r0 *model.StatsImportResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.StatsExportEntry) (*model.StatsImportResult, error)); ok {
+		return returnFunc(ctx, entries)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.StatsExportEntry) *model.StatsImportResult); ok {
+		r0 = returnFunc(ctx, entries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsImportResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []model.StatsExportEntry) error); ok {
+		r1 = returnFunc(ctx, entries)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsRepository_ImportEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportEntries'
+type MockIStatsRepository_ImportEntries_Call struct {
+	*mock.Call
+}
+
+// ImportEntries is a helper method to define mock.On call
+//   - ctx
+//   - entries
+func (_e *MockIStatsRepository_Expecter) ImportEntries(ctx interface{}, entries interface{}) *MockIStatsRepository_ImportEntries_Call {
+	return &MockIStatsRepository_ImportEntries_Call{Call: _e.mock.On("ImportEntries", ctx, entries)}
+}
+
+func (_c *MockIStatsRepository_ImportEntries_Call) Run(run func(ctx context.Context, entries []model.StatsExportEntry)) *MockIStatsRepository_ImportEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]model.StatsExportEntry))
+	})
+	return _c
+}
+
+func (_c *MockIStatsRepository_ImportEntries_Call) Return(statsImportResult *model.StatsImportResult, err error) *MockIStatsRepository_ImportEntries_Call {
+	_c.Call.Return(statsImportResult, err)
+	return _c
+}
+
+func (_c *MockIStatsRepository_ImportEntries_Call) RunAndReturn(run func(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error)) *MockIStatsRepository_ImportEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RecordRequest provides a mock function for the type MockIStatsRepository
 func (_mock *MockIStatsRepository) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) error {
 	ret := _mock.Called(ctx, request)
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,176 @@ func (_m *MockIStatsService) EXPECT() *MockIStatsService_Expecter {
 	return &MockIStatsService_Expecter{mock: &_m.Mock}
 }
 
+// DeleteStatsEntry provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) DeleteStatsEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStatsEntry")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_DeleteStatsEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStatsEntry'
+type MockIStatsService_DeleteStatsEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteStatsEntry is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockIStatsService_Expecter) DeleteStatsEntry(ctx interface{}, id interface{}) *MockIStatsService_DeleteStatsEntry_Call {
+	return &MockIStatsService_DeleteStatsEntry_Call{Call: _e.mock.On("DeleteStatsEntry", ctx, id)}
+}
+
+func (_c *MockIStatsService_DeleteStatsEntry_Call) Run(run func(ctx context.Context, id uint)) *MockIStatsService_DeleteStatsEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_DeleteStatsEntry_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsService_DeleteStatsEntry_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsService_DeleteStatsEntry_Call) RunAndReturn(run func(ctx context.Context, id uint) (*model.StatsDeleteResult, error)) *MockIStatsService_DeleteStatsEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteStatsRequest provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) DeleteStatsRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStatsRequest")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.FizzBuzzRequest) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.FizzBuzzRequest) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.FizzBuzzRequest) error); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_DeleteStatsRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStatsRequest'
+type MockIStatsService_DeleteStatsRequest_Call struct {
+	*mock.Call
+}
+
+// DeleteStatsRequest is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockIStatsService_Expecter) DeleteStatsRequest(ctx interface{}, request interface{}) *MockIStatsService_DeleteStatsRequest_Call {
+	return &MockIStatsService_DeleteStatsRequest_Call{Call: _e.mock.On("DeleteStatsRequest", ctx, request)}
+}
+
+func (_c *MockIStatsService_DeleteStatsRequest_Call) Run(run func(ctx context.Context, request model.FizzBuzzRequest)) *MockIStatsService_DeleteStatsRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.FizzBuzzRequest))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_DeleteStatsRequest_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsService_DeleteStatsRequest_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsService_DeleteStatsRequest_Call) RunAndReturn(run func(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error)) *MockIStatsService_DeleteStatsRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportStats provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) ExportStats(ctx context.Context) (*model.StatsExport, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportStats")
+	}
+
+	var r0 *model.StatsExport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.StatsExport, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.StatsExport); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsExport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_ExportStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportStats'
+type MockIStatsService_ExportStats_Call struct {
+	*mock.Call
+}
+
+// ExportStats is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsService_Expecter) ExportStats(ctx interface{}) *MockIStatsService_ExportStats_Call {
+	return &MockIStatsService_ExportStats_Call{Call: _e.mock.On("ExportStats", ctx)}
+}
+
+func (_c *MockIStatsService_ExportStats_Call) Run(run func(ctx context.Context)) *MockIStatsService_ExportStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_ExportStats_Call) Return(statsExport *model.StatsExport, err error) *MockIStatsService_ExportStats_Call {
+	_c.Call.Return(statsExport, err)
+	return _c
+}
+
+func (_c *MockIStatsService_ExportStats_Call) RunAndReturn(run func(ctx context.Context) (*model.StatsExport, error)) *MockIStatsService_ExportStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMostFrequent provides a mock function for the type MockIStatsService
 func (_mock *MockIStatsService) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
 	ret := _mock.Called(ctx)
@@ -93,3 +264,288 @@ func (_c *MockIStatsService_GetMostFrequent_Call) RunAndReturn(run func(ctx cont
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetMostFrequentBetween provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) GetMostFrequentBetween(ctx context.Context, since time.Time, until time.Time) (*model.StatsResponse, error) {
+	ret := _mock.Called(ctx, since, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMostFrequentBetween")
+	}
+
+	var r0 *model.StatsResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (*model.StatsResponse, error)); ok {
+		return returnFunc(ctx, since, until)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *model.StatsResponse); ok {
+		r0 = returnFunc(ctx, since, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, since, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_GetMostFrequentBetween_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMostFrequentBetween'
+type MockIStatsService_GetMostFrequentBetween_Call struct {
+	*mock.Call
+}
+
+// GetMostFrequentBetween is a helper method to define mock.On call
+//   - ctx
+//   - since
+//   - until
+func (_e *MockIStatsService_Expecter) GetMostFrequentBetween(ctx interface{}, since interface{}, until interface{}) *MockIStatsService_GetMostFrequentBetween_Call {
+	return &MockIStatsService_GetMostFrequentBetween_Call{Call: _e.mock.On("GetMostFrequentBetween", ctx, since, until)}
+}
+
+func (_c *MockIStatsService_GetMostFrequentBetween_Call) Run(run func(ctx context.Context, since time.Time, until time.Time)) *MockIStatsService_GetMostFrequentBetween_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_GetMostFrequentBetween_Call) Return(statsResponse *model.StatsResponse, err error) *MockIStatsService_GetMostFrequentBetween_Call {
+	_c.Call.Return(statsResponse, err)
+	return _c
+}
+
+func (_c *MockIStatsService_GetMostFrequentBetween_Call) RunAndReturn(run func(ctx context.Context, since time.Time, until time.Time) (*model.StatsResponse, error)) *MockIStatsService_GetMostFrequentBetween_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStoreMetrics provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoreMetrics")
+	}
+
+	var r0 *model.StatsStoreMetrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.StatsStoreMetrics, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.StatsStoreMetrics); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsStoreMetrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_GetStoreMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStoreMetrics'
+type MockIStatsService_GetStoreMetrics_Call struct {
+	*mock.Call
+}
+
+// GetStoreMetrics is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsService_Expecter) GetStoreMetrics(ctx interface{}) *MockIStatsService_GetStoreMetrics_Call {
+	return &MockIStatsService_GetStoreMetrics_Call{Call: _e.mock.On("GetStoreMetrics", ctx)}
+}
+
+func (_c *MockIStatsService_GetStoreMetrics_Call) Run(run func(ctx context.Context)) *MockIStatsService_GetStoreMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_GetStoreMetrics_Call) Return(statsStoreMetrics *model.StatsStoreMetrics, err error) *MockIStatsService_GetStoreMetrics_Call {
+	_c.Call.Return(statsStoreMetrics, err)
+	return _c
+}
+
+func (_c *MockIStatsService_GetStoreMetrics_Call) RunAndReturn(run func(ctx context.Context) (*model.StatsStoreMetrics, error)) *MockIStatsService_GetStoreMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTop provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) GetTop(ctx context.Context, n int, offset int) (*model.StatsTopResponse, error) {
+	ret := _mock.Called(ctx, n, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTop")
+	}
+
+	var r0 *model.StatsTopResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*model.StatsTopResponse, error)); ok {
+		return returnFunc(ctx, n, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *model.StatsTopResponse); ok {
+		r0 = returnFunc(ctx, n, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsTopResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, n, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_GetTop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTop'
+type MockIStatsService_GetTop_Call struct {
+	*mock.Call
+}
+
+// GetTop is a helper method to define mock.On call
+//   - ctx
+//   - n
+//   - offset
+func (_e *MockIStatsService_Expecter) GetTop(ctx interface{}, n interface{}, offset interface{}) *MockIStatsService_GetTop_Call {
+	return &MockIStatsService_GetTop_Call{Call: _e.mock.On("GetTop", ctx, n, offset)}
+}
+
+func (_c *MockIStatsService_GetTop_Call) Run(run func(ctx context.Context, n int, offset int)) *MockIStatsService_GetTop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_GetTop_Call) Return(statsTopResponse *model.StatsTopResponse, err error) *MockIStatsService_GetTop_Call {
+	_c.Call.Return(statsTopResponse, err)
+	return _c
+}
+
+func (_c *MockIStatsService_GetTop_Call) RunAndReturn(run func(ctx context.Context, n int, offset int) (*model.StatsTopResponse, error)) *MockIStatsService_GetTop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportStats provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) ImportStats(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error) {
+	ret := _mock.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportStats")
+	}
+
+	var r0 *model.StatsImportResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.StatsExportEntry) (*model.StatsImportResult, error)); ok {
+		return returnFunc(ctx, entries)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.StatsExportEntry) *model.StatsImportResult); ok {
+		r0 = returnFunc(ctx, entries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsImportResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []model.StatsExportEntry) error); ok {
+		r1 = returnFunc(ctx, entries)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_ImportStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportStats'
+type MockIStatsService_ImportStats_Call struct {
+	*mock.Call
+}
+
+// ImportStats is a helper method to define mock.On call
+//   - ctx
+//   - entries
+func (_e *MockIStatsService_Expecter) ImportStats(ctx interface{}, entries interface{}) *MockIStatsService_ImportStats_Call {
+	return &MockIStatsService_ImportStats_Call{Call: _e.mock.On("ImportStats", ctx, entries)}
+}
+
+func (_c *MockIStatsService_ImportStats_Call) Run(run func(ctx context.Context, entries []model.StatsExportEntry)) *MockIStatsService_ImportStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]model.StatsExportEntry))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_ImportStats_Call) Return(statsImportResult *model.StatsImportResult, err error) *MockIStatsService_ImportStats_Call {
+	_c.Call.Return(statsImportResult, err)
+	return _c
+}
+
+func (_c *MockIStatsService_ImportStats_Call) RunAndReturn(run func(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error)) *MockIStatsService_ImportStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetStats provides a mock function for the type MockIStatsService
+func (_mock *MockIStatsService) ResetStats(ctx context.Context) (*model.StatsDeleteResult, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetStats")
+	}
+
+	var r0 *model.StatsDeleteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.StatsDeleteResult, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.StatsDeleteResult); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StatsDeleteResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStatsService_ResetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetStats'
+type MockIStatsService_ResetStats_Call struct {
+	*mock.Call
+}
+
+// ResetStats is a helper method to define mock.On call
+//   - ctx
+func (_e *MockIStatsService_Expecter) ResetStats(ctx interface{}) *MockIStatsService_ResetStats_Call {
+	return &MockIStatsService_ResetStats_Call{Call: _e.mock.On("ResetStats", ctx)}
+}
+
+func (_c *MockIStatsService_ResetStats_Call) Run(run func(ctx context.Context)) *MockIStatsService_ResetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStatsService_ResetStats_Call) Return(statsDeleteResult *model.StatsDeleteResult, err error) *MockIStatsService_ResetStats_Call {
+	_c.Call.Return(statsDeleteResult, err)
+	return _c
+}
+
+func (_c *MockIStatsService_ResetStats_Call) RunAndReturn(run func(ctx context.Context) (*model.StatsDeleteResult, error)) *MockIStatsService_ResetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -1,22 +1,134 @@
 package model
 
+import "encoding/xml"
+
+// Rule replaces multiples of Divisor with Word. When several rules match,
+// their words are concatenated in rule order.
+// The divisor and word tags check the bounds set in config.AppConfig.
+type Rule struct {
+	Divisor int    `json:"divisor" validate:"required,divisor"`
+	Word    string `json:"word" validate:"required,word"`
+}
+
+// FizzBuzzRequest accepts either an explicit list of rules or the legacy
+// int1/str1 + int2/str2 pair, which is shorthand for a two-rule list.
+// Start, End, PageSize and Cursor optionally restrict the response to a window of the sequence,
+// and Locale, LocalizeWords and NumberFormat change how it is written.
 type FizzBuzzRequest struct {
-	Int1  int    `json:"int1" validate:"required,min=1"`
-	Int2  int    `json:"int2" validate:"required,min=1"`
-	Limit int    `json:"limit" validate:"required,min=1,max=10000"`
-	Str1  string `json:"str1" validate:"required,min=1,max=100"`
-	Str2  string `json:"str2" validate:"required,min=1,max=100"`
+	Int1     int    `json:"int1,omitempty" query:"int1" validate:"required_without=Rules,omitempty,divisor"`
+	Int2     int    `json:"int2,omitempty" query:"int2" validate:"required_without=Rules,omitempty,divisor"`
+	Limit    int    `json:"limit" query:"limit" validate:"required,min=1"`
+	Str1     string `json:"str1,omitempty" query:"str1" validate:"required_without=Rules,omitempty,word"`
+	Str2     string `json:"str2,omitempty" query:"str2" validate:"required_without=Rules,omitempty,word"`
+	Rules    []Rule `json:"rules,omitempty" validate:"omitempty,max=10,dive"`
+	Start    int    `json:"start,omitempty" query:"start" validate:"omitempty,min=1"`
+	End      int    `json:"end,omitempty" query:"end" validate:"omitempty,min=1"`
+	PageSize int    `json:"page_size,omitempty" query:"page_size" validate:"omitempty,min=1,page_size"`
+	Cursor   string `json:"cursor,omitempty" query:"cursor"`
+	// Locale renders the numbers (digits, thousands separators) of a language, or of the Accept-Language header with "auto";
+	// LocalizeWords also replaces the words that have a preset in the locale (fizz, buzz)
+	Locale        string `json:"locale,omitempty" query:"locale"`
+	LocalizeWords bool   `json:"localize_words,omitempty" query:"localize_words" validate:"excluded_without=Locale"`
+	// NumberFormat writes numbers in decimal (default), binary, octal, hex, base2 to base36 or roman; only decimal goes with Locale
+	NumberFormat string `json:"number_format,omitempty" query:"number_format"`
+}
+
+// HasLegacyParams reports whether any of the int1/int2/str1/str2 fields is set.
+func (r FizzBuzzRequest) HasLegacyParams() bool {
+	return r.Int1 != 0 || r.Int2 != 0 || r.Str1 != "" || r.Str2 != ""
+}
+
+// IsWindowed reports whether the request asks for a range or a page of the sequence
+// rather than the whole sequence.
+func (r FizzBuzzRequest) IsWindowed() bool {
+	return r.Start != 0 || r.End != 0 || r.PageSize != 0 || r.Cursor != ""
+}
+
+// Window returns the first and last numbers of the requested range, defaulting to 1..Limit.
+func (r FizzBuzzRequest) Window() (start, end int) {
+	start, end = 1, r.Limit
+	if r.Start != 0 {
+		start = r.Start
+	}
+	if r.End != 0 {
+		end = r.End
+	}
+	return start, end
+}
+
+// EffectiveRules returns the rules to apply, expanding the legacy fields
+// when no explicit rules were given.
+func (r FizzBuzzRequest) EffectiveRules() []Rule {
+	if len(r.Rules) > 0 {
+		return r.Rules
+	}
+	return []Rule{
+		{Divisor: r.Int1, Word: r.Str1},
+		{Divisor: r.Int2, Word: r.Str2},
+	}
+}
+
+// NewFizzBuzzRequest builds a request from a rule list, using the legacy
+// shape when there are exactly two rules so both forms compare equal.
+func NewFizzBuzzRequest(rules []Rule, limit int) FizzBuzzRequest {
+	if len(rules) == 2 {
+		return FizzBuzzRequest{
+			Int1:  rules[0].Divisor,
+			Int2:  rules[1].Divisor,
+			Limit: limit,
+			Str1:  rules[0].Word,
+			Str2:  rules[1].Word,
+		}
+	}
+	return FizzBuzzRequest{
+		Limit: limit,
+		Rules: rules,
+	}
 }
 
 type FizzBuzzResponse struct {
-	Result []string `json:"result"`
-	Count  int      `json:"count"`
+	XMLName    xml.Name `json:"-" xml:"fizzbuzz"`
+	Result     []string `json:"result" xml:"result>value"`
+	Count      int      `json:"count" xml:"count"`
+	Start      int      `json:"start,omitempty" xml:"start,omitempty"`             // Number whose value is Result[0]
+	Total      int      `json:"total,omitempty" xml:"total,omitempty"`             // Values in the requested range, across all pages
+	NextCursor string   `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"` // Set while pages remain; pass it back as "cursor"
+}
+
+// FizzBuzzBatchItem is the outcome of one request of a batch: exactly one of Response and Error is set.
+type FizzBuzzBatchItem struct {
+	Index    int                      `json:"index"`
+	Response *FizzBuzzResponse        `json:"response,omitempty"`
+	Error    *ValidationErrorResponse `json:"error,omitempty"`
+}
+
+type FizzBuzzBatchResponse struct {
+	Items     []FizzBuzzBatchItem `json:"items"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
 }
 
+// ErrorResponse is the body of every error response, see errors.HTTPErrorHandler
 type ErrorResponse struct {
-	Error   string            `json:"error"`
-	Message string            `json:"message"`
-	Details map[string]string `json:"details,omitempty"`
+	Error     string            `json:"error"`                // Stable code: the name of the ControllerError, e.g. ValidationLimitError
+	Message   string            `json:"message"`              // Translated according to Accept-Language
+	RequestID string            `json:"request_id,omitempty"` // X-Request-ID, to find the request in the logs
+	Details   map[string]string `json:"details,omitempty"`
+	Errors    []ValidationError `json:"errors,omitempty"` // Invalid fields, for validation errors
+}
+
+// ProblemDetails is the RFC 7807 form of ErrorResponse, sent as application/problem+json.
+// Code, RequestID, Details and Errors are extension members holding the fields of ErrorResponse.
+type ProblemDetails struct {
+	Type      string            `json:"type"`  // urn:fizzbuzz-api:error:<code>
+	Title     string            `json:"title"` // HTTP status text
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail"`   // Translated message
+	Instance  string            `json:"instance"` // Request path and query
+	Code      string            `json:"code"`
+	RequestID string            `json:"request_id,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Errors    []ValidationError `json:"errors,omitempty"`
 }
 
 type ValidationError struct {
@@ -29,4 +141,4 @@ type ValidationErrorResponse struct {
 	Error   string            `json:"error"`
 	Message string            `json:"message"`
 	Errors  []ValidationError `json:"errors"`
-}
\ No newline at end of file
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
 
 // StatsEntry represents a fizzbuzz request statistics record in the database
 type StatsEntry struct {
@@ -10,13 +14,86 @@ type StatsEntry struct {
 	Limit     int       `gorm:"not null;uniqueIndex:idx_params" json:"-"`
 	Str1      string    `gorm:"not null;size:100;uniqueIndex:idx_params" json:"-"`
 	Str2      string    `gorm:"not null;size:100;uniqueIndex:idx_params" json:"-"`
+	Rules     string    `gorm:"not null;default:''" json:"-"`                                // JSON-encoded rules, empty for the legacy two-rule shape
+	RulesHash string    `gorm:"not null;size:64;default:'';uniqueIndex:idx_params" json:"-"` // HashRules(Rules): rules can be too long for an index
 	HitCount  int64     `gorm:"not null;default:0;index" json:"hit_count"`
 	CreatedAt time.Time `json:"-"`
 	UpdatedAt time.Time `json:"-"`
 }
 
+// HashRules returns the SHA-256 of JSON-encoded rules in hexadecimal, and an empty string for no rules.
+// It stands for the rules in the unique index of stats entries, whose rows PostgreSQL limits to 2704 bytes.
+func HashRules(rules string) string {
+	if rules == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(rules))
+	return hex.EncodeToString(sum[:])
+}
+
+// StatsBucket counts the hits of a stats entry during one time bucket (minute, hour or day)
+type StatsBucket struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	EntryID     uint      `gorm:"not null;uniqueIndex:idx_entry_bucket" json:"-"`
+	BucketStart time.Time `gorm:"not null;uniqueIndex:idx_entry_bucket;index" json:"-"`
+	HitCount    int64     `gorm:"not null;default:0" json:"-"`
+}
+
 // StatsResponse represents the API response for the most frequent request
 type StatsResponse struct {
+	ID       uint            `json:"id"` // Identifies the entry in DELETE /stats/{id}
 	Request  FizzBuzzRequest `json:"request"`
 	HitCount int64           `json:"hit_count"`
+	Since    *time.Time      `json:"since,omitempty"` // Set for windowed queries: hit_count only covers since..until
+	Until    *time.Time      `json:"until,omitempty"`
+}
+
+// StatsTopResponse represents a page of the most frequent requests
+type StatsTopResponse struct {
+	Items  []StatsResponse `json:"items"`
+	N      int             `json:"n"`
+	Offset int             `json:"offset"`
+	Total  int64           `json:"total"`
+}
+
+// StatsStoreMetrics describes the stats store, so operators can tell when the in-memory cap is hit
+type StatsStoreMetrics struct {
+	Storage        string `json:"storage"`
+	Entries        int64  `json:"entries"`
+	MaxEntries     int    `json:"max_entries,omitempty"`
+	EvictionPolicy string `json:"eviction_policy,omitempty"`
+	EntryTTL       string `json:"entry_ttl,omitempty"`
+	Evictions      int64  `json:"evictions"`       // Entries removed to stay under max_entries
+	Expirations    int64  `json:"expirations"`     // Entries removed after entry_ttl without requests
+	PendingHits    int64  `json:"pending_hits"`    // Hits buffered in memory, not yet written to the database
+	DroppedHits    int64  `json:"dropped_hits"`    // Buffered hits dropped because flushes kept failing or the database refused them
+	FlushErrors    int64  `json:"flush_errors"`    // Failed batch writes, retried at the next flush
+	SnapshotErrors int64  `json:"snapshot_errors"` // Failed saves of the memory store, retried at the next snapshot
+}
+
+// StatsExportEntry is a stats entry as exported and imported by the admin endpoints: its parameter set
+// and all-time hit count. Hits per time bucket are not exported.
+type StatsExportEntry struct {
+	Request   FizzBuzzRequest `json:"request"`
+	HitCount  int64           `json:"hit_count"`
+	CreatedAt time.Time       `json:"created_at"` // First request
+	UpdatedAt time.Time       `json:"updated_at"` // Last request
+}
+
+// StatsExport is the JSON document of a stats export
+type StatsExport struct {
+	ExportedAt time.Time          `json:"exported_at"`
+	Entries    []StatsExportEntry `json:"entries"`
+}
+
+// StatsImportResult summarizes an import: hits of parameter sets already stored were added to theirs
+type StatsImportResult struct {
+	Entries int   `json:"entries"` // Distinct parameter sets imported
+	Hits    int64 `json:"hits"`    // Hits added
+}
+
+// StatsDeleteResult reports what DELETE /stats removed
+type StatsDeleteResult struct {
+	Entries int64 `json:"entries"` // Distinct parameter sets deleted
+	Hits    int64 `json:"hits"`    // Their hits
 }
@@ -0,0 +1,531 @@
+// Package repositorytest checks that a stats backend behaves like the ones shipped with the API.
+package repositorytest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+	"github.com/julietteengel/fizzbuzz-api/internal/repository"
+)
+
+// NewStatsRepository returns an empty repository of the backend under test, configured with stats
+// and closed by the suite
+type NewStatsRepository func(t *testing.T, stats config.StatsConfig) repository.IStatsRepository
+
+// RunStatsRepositorySuite runs every conformance test on repositories built by newRepo. Backends
+// that buffer hits implement repository.IStatsFlusher: the suite flushes them before each read.
+// Tests of time buckets need repository.IStatsClock and are skipped on backends without it.
+func RunStatsRepositorySuite(t *testing.T, newRepo NewStatsRepository) {
+	tests := []struct {
+		name  string
+		stats config.StatsConfig
+		test  func(t *testing.T, repo repository.IStatsRepository)
+	}{
+		{name: "Empty", test: testEmpty},
+		{name: "RecordAndGetMostFrequent", test: testRecordAndGetMostFrequent},
+		{name: "RequestShapes", test: testRequestShapes},
+		{name: "LongRules", test: testLongRules},
+		{name: "GetTop", test: testGetTop},
+		{name: "TieBreak", test: testTieBreak},
+		{name: "GetMostFrequentBetween", test: testGetMostFrequentBetween},
+		{name: "WindowTieBreak", test: testWindowTieBreak},
+		{
+			name:  "BucketRetention",
+			stats: config.StatsConfig{BucketGranularity: time.Hour, BucketRetention: 48 * time.Hour},
+			test:  testBucketRetention,
+		},
+		{name: "ConcurrentRecordRequest", test: testConcurrentRecordRequest},
+		{name: "ExportEntries", test: testExportEntries},
+		{name: "ImportEntries", test: testImportEntries},
+		{name: "DeleteAll", test: testDeleteAll},
+		{name: "DeleteEntry", test: testDeleteEntry},
+		{name: "DeleteRequest", test: testDeleteRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t, tt.stats)
+			t.Cleanup(func() { assert.NoError(t, repo.Close()) })
+
+			tt.test(t, repo)
+		})
+	}
+}
+
+// record records request hits times then flushes, so that reads see every hit
+func record(t *testing.T, repo repository.IStatsRepository, request model.FizzBuzzRequest, hits int) {
+	t.Helper()
+	for i := 0; i < hits; i++ {
+		require.NoError(t, repo.RecordRequest(context.Background(), request))
+	}
+	flush(t, repo)
+}
+
+// withoutID clears the ID of response: backends assign IDs that tests cannot predict
+func withoutID(response *model.StatsResponse) *model.StatsResponse {
+	if response != nil {
+		response.ID = 0
+	}
+	return response
+}
+
+func withoutIDs(responses []model.StatsResponse) []model.StatsResponse {
+	for i := range responses {
+		responses[i].ID = 0
+	}
+	return responses
+}
+
+func flush(t *testing.T, repo repository.IStatsRepository) {
+	t.Helper()
+	if flusher, ok := repo.(repository.IStatsFlusher); ok {
+		require.NoError(t, flusher.Flush(context.Background()))
+	}
+}
+
+func testEmpty(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+
+	result, err := repo.GetMostFrequent(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	top, total, err := repo.GetTop(ctx, 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, top)
+	assert.Equal(t, int64(0), total)
+
+	now := time.Now()
+	result, err = repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	metrics, err := repo.GetStoreMetrics(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), metrics.Entries)
+}
+
+func testRecordAndGetMostFrequent(t *testing.T, repo repository.IStatsRepository) {
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	record(t, repo, fizzBuzz, 2)
+	record(t, repo, fooBar, 3)
+
+	result, err := repo.GetMostFrequent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fooBar, HitCount: 3}, withoutID(result))
+
+	metrics, err := repo.GetStoreMetrics(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), metrics.Entries)
+	assert.Equal(t, int64(0), metrics.PendingHits)
+}
+
+// testRequestShapes checks that a two-rule request counts the same in legacy and rules form,
+// and that any other rule count is stored and returned as is
+func testRequestShapes(t *testing.T, repo repository.IStatsRepository) {
+	legacy := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	sameAsRules := model.FizzBuzzRequest{Limit: 100, Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}}}
+	threeRules := model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "a"}, {Divisor: 5, Word: "b"}, {Divisor: 7, Word: "c"}}}
+	oneRule := model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}
+
+	record(t, repo, legacy, 2)
+	record(t, repo, sameAsRules, 2)
+	record(t, repo, threeRules, 3)
+	record(t, repo, oneRule, 1)
+
+	top, total, err := repo.GetTop(context.Background(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []model.StatsResponse{
+		{Request: legacy, HitCount: 4},
+		{Request: threeRules, HitCount: 3},
+		{Request: oneRule, HitCount: 1},
+	}, withoutIDs(top))
+}
+
+// testLongRules records the largest rules a request can have: 10 words of 100 characters, each 3 bytes
+// in UTF-8 or 6 once HTML-escaped by JSON, far beyond the size of a PostgreSQL index row
+func testLongRules(t *testing.T, repo repository.IStatsRepository) {
+	request := model.FizzBuzzRequest{Limit: 100}
+	for i := 1; i <= 10; i++ {
+		request.Rules = append(request.Rules, model.Rule{Divisor: i, Word: strings.Repeat("€<", 50)})
+	}
+	other := model.FizzBuzzRequest{Limit: 100, Rules: append([]model.Rule{}, request.Rules...)}
+	other.Rules[9].Word += "!"
+
+	record(t, repo, request, 2)
+	record(t, repo, other, 1)
+
+	top, total, err := repo.GetTop(context.Background(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []model.StatsResponse{{Request: request, HitCount: 2}, {Request: other, HitCount: 1}}, withoutIDs(top))
+
+	result, err := repo.DeleteRequest(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 1, Hits: 2}, result)
+}
+
+func testGetTop(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	requests := []model.FizzBuzzRequest{
+		{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"},
+		{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"},
+		{Int1: 1, Int2: 10, Limit: 200, Str1: "a", Str2: "b"},
+	}
+	for i, hits := range []int{1, 5, 3, 2} {
+		record(t, repo, requests[i], hits)
+	}
+
+	top, total, err := repo.GetTop(ctx, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []model.StatsResponse{
+		{Request: requests[1], HitCount: 5},
+		{Request: requests[2], HitCount: 3},
+	}, withoutIDs(top))
+
+	top, total, err = repo.GetTop(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []model.StatsResponse{
+		{Request: requests[3], HitCount: 2},
+		{Request: requests[0], HitCount: 1},
+	}, withoutIDs(top))
+
+	top, total, err = repo.GetTop(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Empty(t, top)
+}
+
+// testTieBreak checks that entries with as many hits rank by first request, whatever the order of later hits
+func testTieBreak(t *testing.T, repo repository.IStatsRepository) {
+	first := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	second := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	third := model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "a"}, {Divisor: 5, Word: "b"}, {Divisor: 7, Word: "c"}}}
+
+	record(t, repo, first, 1)
+	record(t, repo, second, 1)
+	record(t, repo, third, 1)
+	record(t, repo, third, 1)
+	record(t, repo, second, 1)
+	record(t, repo, first, 1)
+
+	result, err := repo.GetMostFrequent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: first, HitCount: 2}, withoutID(result))
+
+	top, _, err := repo.GetTop(context.Background(), 3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []model.StatsResponse{
+		{Request: first, HitCount: 2},
+		{Request: second, HitCount: 2},
+		{Request: third, HitCount: 2},
+	}, withoutIDs(top))
+
+	now := time.Now()
+	result, err = repo.GetMostFrequentBetween(context.Background(), now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: first, HitCount: 2}, withoutID(result))
+}
+
+func testGetMostFrequentBetween(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	record(t, repo, fizzBuzz, 2)
+	record(t, repo, fooBar, 3)
+
+	now := time.Now()
+	result, err := repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fooBar, HitCount: 3}, withoutID(result))
+
+	// No bucket starts in a window entirely in the future
+	result, err = repo.GetMostFrequentBetween(ctx, now.Add(48*time.Hour), now.Add(72*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+// testBucketRetention checks windows against hits recorded at chosen times, and that buckets older
+// than the retention are pruned from windows but not from all-time stats
+func testBucketRetention(t *testing.T, repo repository.IStatsRepository) {
+	clock, ok := repo.(repository.IStatsClock)
+	if !ok {
+		t.Skip("backend does not implement repository.IStatsClock")
+	}
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Limit: 50, Rules: []model.Rule{{Divisor: 2, Word: "foo"}}}
+
+	clock.SetNow(func() time.Time { return base })
+	record(t, repo, fizzBuzz, 5)
+	clock.SetNow(func() time.Time { return base.Add(2 * time.Hour) })
+	record(t, repo, fooBar, 3)
+	record(t, repo, fizzBuzz, 1)
+
+	result, err := repo.GetMostFrequentBetween(ctx, base.Add(90*time.Minute), base.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fooBar, HitCount: 3}, withoutID(result))
+
+	result, err = repo.GetMostFrequentBetween(ctx, base, base.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fizzBuzz, HitCount: 6}, withoutID(result))
+
+	// Three days later, opening a bucket prunes the ones older than the 48h retention
+	clock.SetNow(func() time.Time { return base.Add(72 * time.Hour) })
+	record(t, repo, fizzBuzz, 1)
+
+	result, err = repo.GetMostFrequentBetween(ctx, base, base.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fooBar, HitCount: 3}, withoutID(result))
+
+	result, err = repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fizzBuzz, HitCount: 7}, withoutID(result))
+}
+
+// testWindowTieBreak checks that entries with as many hits in the window rank by first request,
+// whatever their all-time hits
+func testWindowTieBreak(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	first := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	second := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	record(t, repo, first, 1)
+	record(t, repo, second, 1)
+	// Imported hits count in all-time stats only
+	_, err := repo.ImportEntries(ctx, []model.StatsExportEntry{{Request: second, HitCount: 5}})
+	require.NoError(t, err)
+
+	mostFrequent, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: second, HitCount: 6}, withoutID(mostFrequent))
+
+	now := time.Now()
+	windowed, err := repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: first, HitCount: 1}, withoutID(windowed))
+}
+
+// testExportEntries checks that exports list every entry in rank order, hits not flushed yet included
+func testExportEntries(t *testing.T, repo repository.IStatsRepository) {
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	threeRules := model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "a"}, {Divisor: 5, Word: "b"}, {Divisor: 7, Word: "c"}}}
+
+	before := time.Now().Add(-time.Second)
+	record(t, repo, fizzBuzz, 1)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.RecordRequest(context.Background(), threeRules))
+	}
+
+	exported, err := repo.ExportEntries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+
+	assert.Equal(t, threeRules, exported[0].Request)
+	assert.Equal(t, int64(2), exported[0].HitCount)
+	assert.Equal(t, fizzBuzz, exported[1].Request)
+	assert.Equal(t, int64(1), exported[1].HitCount)
+	for _, entry := range exported {
+		assert.True(t, entry.CreatedAt.After(before), "created_at %v", entry.CreatedAt)
+		assert.False(t, entry.UpdatedAt.Before(entry.CreatedAt))
+	}
+}
+
+// testImportEntries checks that imported hits add up with stored ones and with each other,
+// keeping the earliest first request, which still breaks ties
+func testImportEntries(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fizzBuzzRules := model.FizzBuzzRequest{Limit: 100, Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}}}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	oneRule := model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}
+
+	record(t, repo, fizzBuzz, 2)
+
+	longAgo := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := repo.ImportEntries(ctx, []model.StatsExportEntry{
+		{Request: fizzBuzz, HitCount: 3, CreatedAt: longAgo, UpdatedAt: longAgo},
+		{Request: fooBar, HitCount: 4, CreatedAt: longAgo.Add(time.Hour), UpdatedAt: longAgo.Add(2 * time.Hour)},
+		{Request: fizzBuzzRules, HitCount: 1, CreatedAt: longAgo.Add(time.Hour), UpdatedAt: longAgo.Add(time.Hour)},
+		{Request: oneRule, HitCount: 1}, // No timestamps
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsImportResult{Entries: 3, Hits: 9}, result)
+
+	// fizzBuzz: 2 hits recorded, then 3 and 1 imported in legacy and rules form
+	top, total, err := repo.GetTop(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []model.StatsResponse{
+		{Request: fizzBuzz, HitCount: 6},
+		{Request: fooBar, HitCount: 4},
+		{Request: oneRule, HitCount: 1},
+	}, withoutIDs(top))
+
+	exported, err := repo.ExportEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, exported, 3)
+	assert.True(t, exported[0].CreatedAt.Equal(longAgo), "created_at %v", exported[0].CreatedAt)
+	assert.True(t, exported[0].UpdatedAt.After(longAgo), "updated_at %v", exported[0].UpdatedAt)
+	assert.True(t, exported[1].UpdatedAt.Equal(longAgo.Add(2*time.Hour)), "updated_at %v", exported[1].UpdatedAt)
+
+	// Tied at 6 hits, fizzBuzz ranks first: its imported first request is the earliest
+	_, err = repo.ImportEntries(ctx, []model.StatsExportEntry{{Request: fooBar, HitCount: 2}})
+	require.NoError(t, err)
+	mostFrequent, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fizzBuzz, HitCount: 6}, withoutID(mostFrequent))
+
+	// Imported hits belong to no time bucket
+	now := time.Now()
+	windowed, err := repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fizzBuzz, HitCount: 2}, withoutID(windowed))
+}
+
+// testDeleteAll checks that a reset removes every entry and bucket, including hits not yet written
+func testDeleteAll(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	oneRule := model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}
+
+	record(t, repo, fizzBuzz, 3)
+	require.NoError(t, repo.RecordRequest(ctx, oneRule)) // Still buffered by write-behind backends
+
+	result, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 2, Hits: 4}, result)
+
+	flush(t, repo)
+	testEmpty(t, repo)
+
+	// Recording starts over from zero
+	record(t, repo, oneRule, 1)
+	mostFrequent, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: oneRule, HitCount: 1}, withoutID(mostFrequent))
+
+	now := time.Now()
+	windowed, err := repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: oneRule, HitCount: 1}, withoutID(windowed))
+}
+
+// testDeleteEntry checks that an entry is deleted by the ID reads return, leaving the others untouched
+func testDeleteEntry(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	record(t, repo, fizzBuzz, 3)
+	record(t, repo, fooBar, 2)
+
+	mostFrequent, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, mostFrequent)
+	require.NotZero(t, mostFrequent.ID)
+
+	result, err := repo.DeleteEntry(ctx, mostFrequent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 1, Hits: 3}, result)
+
+	top, total, err := repo.GetTop(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []model.StatsResponse{{Request: fooBar, HitCount: 2}}, withoutIDs(top))
+
+	// Its buckets are gone too
+	now := time.Now()
+	windowed, err := repo.GetMostFrequentBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{Request: fooBar, HitCount: 2}, withoutID(windowed))
+
+	// Deleting it again, or an unknown ID, deletes nothing
+	result, err = repo.DeleteEntry(ctx, mostFrequent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{}, result)
+}
+
+// testDeleteRequest checks that a parameter set is deleted whichever form (legacy or rules) it is given in
+func testDeleteRequest(t *testing.T, repo repository.IStatsRepository) {
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fizzBuzzRules := model.FizzBuzzRequest{Limit: 100, Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}}}
+	oneRule := model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}
+	otherLimit := model.FizzBuzzRequest{Limit: 20, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}
+
+	record(t, repo, fizzBuzz, 2)
+	record(t, repo, oneRule, 3)
+	record(t, repo, otherLimit, 1)
+
+	result, err := repo.DeleteRequest(ctx, fizzBuzzRules)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 1, Hits: 2}, result)
+
+	result, err = repo.DeleteRequest(ctx, oneRule)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 1, Hits: 3}, result)
+
+	result, err = repo.DeleteRequest(ctx, fizzBuzz)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{}, result)
+
+	top, total, err := repo.GetTop(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []model.StatsResponse{{Request: otherLimit, HitCount: 1}}, withoutIDs(top))
+}
+
+// testConcurrentRecordRequest checks that every hit is counted exactly once, including the first
+// hits of a new parameter set racing to create its entry
+func testConcurrentRecordRequest(t *testing.T, repo repository.IStatsRepository) {
+	const workers = 10
+	const requestsPerWorker = 20
+
+	requests := []model.FizzBuzzRequest{
+		{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}, {Divisor: 7, Word: "bazz"}}},
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < requestsPerWorker; i++ {
+				for _, request := range requests {
+					assert.NoError(t, repo.RecordRequest(context.Background(), request))
+				}
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	flush(t, repo)
+
+	top, total, err := repo.GetTop(context.Background(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(requests)), total)
+	require.Len(t, top, len(requests))
+	for _, item := range top {
+		assert.Equal(t, int64(workers*requestsPerWorker), item.HitCount)
+	}
+}
@@ -0,0 +1,400 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+const (
+	// flushTimeout is the deadline of one batch write
+	flushTimeout = 30 * time.Second
+	// flushChunkSize keeps each INSERT well under the 65535 bind parameters PostgreSQL accepts
+	flushChunkSize = 1000
+	// pendingEntriesFactor bounds the batch to this many times FlushMaxEntries: while the database is down,
+	// hits of other parameter sets are dropped instead of growing the batch forever
+	pendingEntriesFactor = 10
+	// maxSplitWrites bounds the writes of a flush looking for the parameter sets the database refuses:
+	// the halves not tried by then are put back in the buffer for the next flush
+	maxSplitWrites = 64
+)
+
+// batchKey identifies buffered hits: one parameter set during one time bucket
+type batchKey struct {
+	entryKey    string
+	bucketStart time.Time
+}
+
+// pendingHits are the hits of a batchKey not yet written. entry.CreatedAt is the first hit, entry.UpdatedAt the last.
+type pendingHits struct {
+	entry model.StatsEntry
+	hits  int64
+}
+
+// recordInBatch buffers a hit until the next flush: one transaction then writes every parameter set
+// requested since the previous flush, instead of one transaction per request
+func (r *gormStatsRepository) recordInBatch(request model.FizzBuzzRequest) error {
+	now := r.now()
+	key := batchKey{entryKey: generateKey(request), bucketStart: r.bucketStart(now)}
+
+	r.batchMutex.Lock()
+	pending, exists := r.batch[key]
+	if !exists && len(r.batch) >= r.maxPendingEntries {
+		r.batchDroppedHits++
+		r.batchMutex.Unlock()
+		return nil
+	}
+	if !exists {
+		pending = &pendingHits{entry: newStatsEntry(request)}
+		pending.entry.CreatedAt = now
+		r.batch[key] = pending
+	}
+	pending.hits++
+	pending.entry.UpdatedAt = now
+	r.batchPendingHits++
+	full := len(r.batch) >= r.flushMaxEntries
+	r.batchMutex.Unlock()
+
+	if full {
+		select {
+		case r.flushSignal <- struct{}{}:
+		default: // A flush is already requested
+		}
+	}
+	return nil
+}
+
+// runBatchFlusher flushes every interval, or earlier when the batch is full, until Close
+func (r *gormStatsRepository) runBatchFlusher(interval time.Duration) {
+	defer close(r.flusherDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.flushSignal:
+		case <-r.stop:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		if err := r.flushBatch(ctx); err != nil {
+			log.Errorf("Failed to flush stats batch, retrying at next flush: %v", err)
+		}
+		cancel()
+	}
+}
+
+// flushBatch writes the buffered hits. When the database is up but refuses the batch, it is written in halves
+// to leave out the parameter sets refused on their own, which are dropped: a value the database can never
+// store must not block every later flush. Other hits are put back in the buffer for the next flush,
+// as long as the buffer stays under maxPendingEntries: the hits that don't fit are dropped.
+func (r *gormStatsRepository) flushBatch(ctx context.Context) error {
+	r.flushMutex.Lock()
+	defer r.flushMutex.Unlock()
+
+	r.batchMutex.Lock()
+	batch := r.batch
+	r.batch = make(map[batchKey]*pendingHits)
+	r.batchMutex.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := r.writeBatch(ctx, batch)
+	var retry, refused map[batchKey]*pendingHits
+	if err != nil {
+		retry = batch
+		if r.databaseUp(ctx) {
+			retry, refused = r.writeInHalves(ctx, batch)
+		}
+	}
+
+	r.batchMutex.Lock()
+	defer r.batchMutex.Unlock()
+
+	// Hits put back in the buffer are counted again below
+	for _, pending := range batch {
+		r.batchPendingHits -= pending.hits
+	}
+	for _, pending := range refused {
+		r.batchDroppedHits += pending.hits
+	}
+	if err == nil {
+		return nil
+	}
+
+	r.flushErrors++
+	for key, pending := range retry {
+		// Hits buffered during the failed write are newer: keep the older first hit time
+		if newer, exists := r.batch[key]; exists {
+			newer.hits += pending.hits
+			newer.entry.CreatedAt = pending.entry.CreatedAt
+		} else if len(r.batch) < r.maxPendingEntries {
+			r.batch[key] = pending
+		} else {
+			r.batchDroppedHits += pending.hits
+			continue
+		}
+		r.batchPendingHits += pending.hits
+	}
+	if len(retry) > 0 {
+		return err
+	}
+	return nil
+}
+
+// writeInHalves writes a batch the database refused whole but not for an outage, in halves down to single
+// parameter sets. It returns the hits to retry, and those of the parameter sets refused on their own.
+func (r *gormStatsRepository) writeInHalves(ctx context.Context, batch map[batchKey]*pendingHits) (retry, refused map[batchKey]*pendingHits) {
+	split := splitFlush{repo: r, retry: make(map[batchKey]*pendingHits), suspects: make(map[batchKey]*pendingHits)}
+	split.write(ctx, batch)
+
+	if !split.written {
+		// Nothing was written: the database may refuse every write, not these parameter sets
+		for key, pending := range split.suspects {
+			split.retry[key] = pending
+		}
+		return split.retry, nil
+	}
+
+	if len(split.suspects) > 0 {
+		log.Errorf("Dropped the stats hits of %d parameter sets refused by the database: %v", len(split.suspects), split.err)
+	}
+	return split.retry, split.suspects
+}
+
+// databaseUp reports whether the database answers, to tell a batch it refuses from an outage
+func (r *gormStatsRepository) databaseUp(ctx context.Context) bool {
+	sqlDB, err := r.db.DB()
+	return err == nil && sqlDB.PingContext(ctx) == nil
+}
+
+// splitFlush writes a batch refused by the database in halves, down to single parameter sets
+type splitFlush struct {
+	repo     *gormStatsRepository
+	writes   int
+	written  bool                      // Some half was written: the database accepts other rows
+	err      error                     // Last error of a single parameter set
+	retry    map[batchKey]*pendingHits // Halves not tried, after maxSplitWrites
+	suspects map[batchKey]*pendingHits // Parameter sets refused on their own
+}
+
+// write writes each half of batch, which was refused whole, and splits again the halves refused
+func (f *splitFlush) write(ctx context.Context, batch map[batchKey]*pendingHits) {
+	halves := splitBatch(batch)
+	if len(halves) == 1 {
+		for key, pending := range batch {
+			f.suspects[key] = pending
+		}
+		return
+	}
+
+	for _, half := range halves {
+		if f.writes >= maxSplitWrites || ctx.Err() != nil {
+			for key, pending := range half {
+				f.retry[key] = pending
+			}
+			continue
+		}
+
+		f.writes++
+		if err := f.repo.writeBatch(ctx, half); err != nil {
+			f.err = err
+			f.write(ctx, half)
+			continue
+		}
+		f.written = true
+	}
+}
+
+// splitBatch divides batch in two by parameter set, keeping the buckets of a parameter set together.
+// A batch of a single parameter set is returned whole.
+func splitBatch(batch map[batchKey]*pendingHits) []map[batchKey]*pendingHits {
+	entryKeys := make([]string, 0, len(batch))
+	seen := make(map[string]bool, len(batch))
+	for key := range batch {
+		if !seen[key.entryKey] {
+			seen[key.entryKey] = true
+			entryKeys = append(entryKeys, key.entryKey)
+		}
+	}
+	if len(entryKeys) == 1 {
+		return []map[batchKey]*pendingHits{batch}
+	}
+
+	sort.Strings(entryKeys)
+	inFirst := make(map[string]bool, len(entryKeys)/2)
+	for _, entryKey := range entryKeys[:len(entryKeys)/2] {
+		inFirst[entryKey] = true
+	}
+
+	first, second := make(map[batchKey]*pendingHits), make(map[batchKey]*pendingHits)
+	for key, pending := range batch {
+		if inFirst[key.entryKey] {
+			first[key] = pending
+		} else {
+			second[key] = pending
+		}
+	}
+	return []map[batchKey]*pendingHits{first, second}
+}
+
+// writeBatch upserts all entries then all buckets of the batch, adding the buffered hits to the stored counts
+func (r *gormStatsRepository) writeBatch(ctx context.Context, batch map[batchKey]*pendingHits) error {
+	// Sum hits per parameter set across buckets
+	entriesByKey := make(map[string]*model.StatsEntry)
+	for key, pending := range batch {
+		entry, exists := entriesByKey[key.entryKey]
+		if !exists {
+			copied := pending.entry
+			copied.HitCount = 0
+			entry = &copied
+			entriesByKey[key.entryKey] = entry
+		}
+		entry.HitCount += pending.hits
+		if pending.entry.CreatedAt.Before(entry.CreatedAt) {
+			entry.CreatedAt = pending.entry.CreatedAt
+		}
+		if pending.entry.UpdatedAt.After(entry.UpdatedAt) {
+			entry.UpdatedAt = pending.entry.UpdatedAt
+		}
+	}
+
+	// Rows are sorted so that concurrent writers (other instances) lock them in the same order
+	entryKeys := make([]string, 0, len(entriesByKey))
+	for key := range entriesByKey {
+		entryKeys = append(entryKeys, key)
+	}
+	sort.Strings(entryKeys)
+
+	entries := make([]model.StatsEntry, 0, len(entryKeys))
+	for _, key := range entryKeys {
+		entries = append(entries, *entriesByKey[key])
+	}
+
+	batchKeys := make([]batchKey, 0, len(batch))
+	for key := range batch {
+		batchKeys = append(batchKeys, key)
+	}
+	sort.Slice(batchKeys, func(i, j int) bool {
+		if batchKeys[i].entryKey != batchKeys[j].entryKey {
+			return batchKeys[i].entryKey < batchKeys[j].entryKey
+		}
+		return batchKeys[i].bucketStart.Before(batchKeys[j].bucketStart)
+	})
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// RETURNING id fills the ID of every entry, inserted or updated
+		err := tx.Clauses(clause.OnConflict{
+			Columns: statsEntryConflictColumns,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"hit_count":  gorm.Expr("stats_entries.hit_count + excluded.hit_count"),
+				"updated_at": gorm.Expr("excluded.updated_at"),
+			}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).CreateInBatches(&entries, flushChunkSize).Error
+		if err != nil {
+			return err
+		}
+
+		entryIDs := make(map[string]uint, len(entries))
+		for i, key := range entryKeys {
+			entryIDs[key] = entries[i].ID
+		}
+
+		buckets := make([]model.StatsBucket, 0, len(batchKeys))
+		for _, key := range batchKeys {
+			buckets = append(buckets, model.StatsBucket{
+				EntryID:     entryIDs[key.entryKey],
+				BucketStart: key.bucketStart,
+				HitCount:    batch[key].hits,
+			})
+		}
+
+		err = tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "entry_id"}, {Name: "bucket_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"hit_count": gorm.Expr("stats_buckets.hit_count + excluded.hit_count"),
+			}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "hit_count"}}}).CreateInBatches(&buckets, flushChunkSize).Error
+		if err != nil {
+			return err
+		}
+
+		cutoff, prune := r.retentionCutoff(r.now())
+		if !prune {
+			return nil
+		}
+
+		// A returned count equal to the buffered hits means this flush opened the bucket: prune the entry's old buckets
+		var openedEntryIDs []uint
+		for i, key := range batchKeys {
+			if buckets[i].HitCount == batch[key].hits {
+				openedEntryIDs = append(openedEntryIDs, buckets[i].EntryID)
+			}
+		}
+		if len(openedEntryIDs) == 0 {
+			return nil
+		}
+		return tx.Where("entry_id IN ? AND bucket_start < ?", openedEntryIDs, cutoff).Delete(&model.StatsBucket{}).Error
+	})
+}
+
+// discardPending removes from the batch the buffered hits of the parameter sets selected by matches,
+// and returns them summed per parameter set
+func (r *gormStatsRepository) discardPending(matches func(key string) bool) map[string]*pendingHits {
+	r.batchMutex.Lock()
+	defer r.batchMutex.Unlock()
+
+	discarded := make(map[string]*pendingHits)
+	for key, pending := range r.batch {
+		if !matches(key.entryKey) {
+			continue
+		}
+		delete(r.batch, key)
+		r.batchPendingHits -= pending.hits
+
+		if sum, exists := discarded[key.entryKey]; exists {
+			sum.hits += pending.hits
+		} else {
+			discarded[key.entryKey] = &pendingHits{entry: pending.entry, hits: pending.hits}
+		}
+	}
+	return discarded
+}
+
+// countStoredEntries counts the parameter sets of pending that have a stored entry
+func countStoredEntries(tx *gorm.DB, pending map[string]*pendingHits) (int64, error) {
+	entries := make([]model.StatsEntry, 0, len(pending))
+	for _, hits := range pending {
+		entries = append(entries, hits.entry)
+	}
+
+	// Six bind parameters per entry: chunks stay under the SQLite limit
+	const chunkSize = 100
+	var stored int64
+	for start := 0; start < len(entries); start += chunkSize {
+		chunk := entries[start:min(start+chunkSize, len(entries))]
+
+		conditions := tx.Session(&gorm.Session{NewDB: true}).Where(entryConditions(chunk[0]))
+		for _, entry := range chunk[1:] {
+			conditions = conditions.Or(entryConditions(entry))
+		}
+
+		var count int64
+		if err := tx.Model(&model.StatsEntry{}).Where(conditions).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		stored += count
+	}
+	return stored, nil
+}
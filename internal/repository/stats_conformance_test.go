@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/database"
+	"github.com/julietteengel/fizzbuzz-api/internal/repository"
+	"github.com/julietteengel/fizzbuzz-api/internal/repository/repositorytest"
+)
+
+func TestStatsRepository_Conformance(t *testing.T) {
+	tests := []struct {
+		name          string
+		storage       string
+		flushInterval time.Duration
+	}{
+		{name: "memory", storage: "memory"},
+		{name: "sqlite", storage: "sqlite"},
+		{name: "sqlite_write_behind", storage: "sqlite", flushInterval: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositorytest.RunStatsRepositorySuite(t, func(t *testing.T, stats config.StatsConfig) repository.IStatsRepository {
+				stats.FlushInterval = tt.flushInterval
+				cfg := &config.Config{
+					Database: config.DatabaseConfig{
+						StatsStorage: tt.storage,
+					},
+					Stats: stats,
+				}
+				var db *gorm.DB
+				if tt.storage != "memory" {
+					var err error
+					db, err = database.OpenSQLite(":memory:")
+					require.NoError(t, err)
+					require.NoError(t, database.MigrateUp(db))
+				}
+
+				repo, err := repository.NewStatsRepository(db, cfg)
+				require.NoError(t, err)
+				return repo
+			})
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// gormStatsRepository keeps stats in an SQL database (PostgreSQL, SQLite) migrated by database.MigrateUp
+type gormStatsRepository struct {
+	bucketClock
+
+	db        *gorm.DB
+	storage   string        // config.Database.StatsStorage: the SQL backend behind db
+	stop      chan struct{} // Closed by Close() to stop the flush goroutine
+	closeOnce sync.Once
+
+	// Write-behind: hits are buffered and written in batches, see stats_batch.go
+	batch             map[batchKey]*pendingHits
+	batchMutex        sync.Mutex
+	batchPendingHits  int64
+	batchDroppedHits  int64 // Hits dropped because the batch reached maxPendingEntries
+	flushMaxEntries   int
+	maxPendingEntries int // Caps the batch while flushes fail, see pendingEntriesFactor
+	flushErrors       int64
+	flushSignal       chan struct{} // Requests an early flush when the batch is full
+	flushMutex        sync.Mutex    // Serializes flushes: the periodic one and the one of Close
+	flusherDone       chan struct{} // Closed when the flush goroutine has exited, nil without batching
+}
+
+func newGormStatsRepository(database *gorm.DB, cfg *config.Config) *gormStatsRepository {
+	repo := &gormStatsRepository{
+		bucketClock: newBucketClock(cfg),
+
+		db:      database,
+		storage: cfg.Database.StatsStorage,
+		stop:    make(chan struct{}),
+
+		batch:           make(map[batchKey]*pendingHits),
+		flushMaxEntries: cfg.Stats.FlushMaxEntries,
+		flushSignal:     make(chan struct{}, 1),
+	}
+
+	if cfg.Stats.FlushInterval > 0 {
+		if repo.flushMaxEntries <= 0 {
+			repo.flushMaxEntries = flushChunkSize
+		}
+		repo.maxPendingEntries = repo.flushMaxEntries * pendingEntriesFactor
+		repo.flusherDone = make(chan struct{})
+		go repo.runBatchFlusher(cfg.Stats.FlushInterval)
+	}
+
+	return repo
+}
+
+// statsEntryConflictColumns are the columns of the idx_params unique index
+var statsEntryConflictColumns = []clause.Column{
+	{Name: "int1"}, {Name: "int2"}, {Name: "limit"}, {Name: "str1"}, {Name: "str2"}, {Name: "rules_hash"},
+}
+
+func (r *gormStatsRepository) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) error {
+	if r.flusherDone != nil {
+		return r.recordInBatch(request)
+	}
+
+	// Un SELECT puis INSERT/UPDATE n'est pas atomique en READ COMMITTED : deux premières requêtes simultanées
+	// ne trouvent aucune ligne et la seconde échoue sur idx_params. INSERT ... ON CONFLICT DO UPDATE laisse
+	// la base sérialiser les écritures sur la ligne : chaque appel compte exactement un hit.
+	// La transaction garde le compteur cumulé et celui du bucket cohérents.
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := r.now()
+		entry := newStatsEntry(request)
+		entry.HitCount = 1
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+
+		// RETURNING id fills entry.ID for both the inserted and the updated row
+		err := tx.Clauses(clause.OnConflict{
+			Columns: statsEntryConflictColumns,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"hit_count":  gorm.Expr("stats_entries.hit_count + 1"),
+				"updated_at": now,
+			}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Create(&entry).Error
+		if err != nil {
+			return err
+		}
+
+		return r.recordBucket(tx, entry.ID, now)
+	})
+}
+
+func (r *gormStatsRepository) recordBucket(tx *gorm.DB, entryID uint, now time.Time) error {
+	bucket := model.StatsBucket{EntryID: entryID, BucketStart: r.bucketStart(now), HitCount: 1}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "entry_id"}, {Name: "bucket_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"hit_count": gorm.Expr("stats_buckets.hit_count + 1"),
+		}),
+	}, clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "hit_count"}}}).Create(&bucket).Error
+	if err != nil {
+		return err
+	}
+
+	// A returned count of 1 means this call opened the bucket: pruning then keeps it to once per bucket and entry
+	if cutoff, prune := r.retentionCutoff(now); prune && bucket.HitCount == 1 {
+		return tx.Where("entry_id = ? AND bucket_start < ?", entryID, cutoff).Delete(&model.StatsBucket{}).Error
+	}
+	return nil
+}
+
+func (r *gormStatsRepository) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
+	var entry model.StatsEntry
+	result := r.db.WithContext(ctx).Order(statsOrder).First(&entry)
+	if result.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return toStatsResponse(entry)
+}
+
+func (r *gormStatsRepository) GetTop(ctx context.Context, n, offset int) ([]model.StatsResponse, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.StatsEntry{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []model.StatsEntry
+	if err := r.db.WithContext(ctx).Order(statsOrder).Limit(n).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	top := make([]model.StatsResponse, 0, len(entries))
+	for _, entry := range entries {
+		response, err := toStatsResponse(entry)
+		if err != nil {
+			return nil, 0, err
+		}
+		top = append(top, *response)
+	}
+	return top, total, nil
+}
+
+func (r *gormStatsRepository) GetMostFrequentBetween(ctx context.Context, since, until time.Time) (*model.StatsResponse, error) {
+	since = r.bucketStart(since)
+
+	var rows []struct {
+		model.StatsEntry
+		WindowHits int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.StatsEntry{}).
+		Select("stats_entries.*, SUM(stats_buckets.hit_count) AS window_hits").
+		Joins("JOIN stats_buckets ON stats_buckets.entry_id = stats_entries.id").
+		Where("stats_buckets.bucket_start >= ? AND stats_buckets.bucket_start < ?", since, until).
+		Group("stats_entries.id").
+		Order("window_hits DESC, stats_entries.created_at ASC, stats_entries.id ASC").
+		Limit(1).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	response, err := toStatsResponse(rows[0].StatsEntry)
+	if err != nil {
+		return nil, err
+	}
+	response.HitCount = rows[0].WindowHits
+	return response, nil
+}
+
+func (r *gormStatsRepository) GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error) {
+	var entries int64
+	if err := r.db.WithContext(ctx).Model(&model.StatsEntry{}).Count(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	r.batchMutex.Lock()
+	defer r.batchMutex.Unlock()
+	return &model.StatsStoreMetrics{
+		Storage:     r.storage,
+		Entries:     entries,
+		PendingHits: r.batchPendingHits,
+		DroppedHits: r.batchDroppedHits,
+		FlushErrors: r.flushErrors,
+	}, nil
+}
+
+func (r *gormStatsRepository) ExportEntries(ctx context.Context) ([]model.StatsExportEntry, error) {
+	// Buffered hits would be missing from the export otherwise
+	if err := r.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	var entries []model.StatsEntry
+	if err := r.db.WithContext(ctx).Order(statsOrder).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	exported := make([]model.StatsExportEntry, 0, len(entries))
+	for _, entry := range entries {
+		exportEntry, err := toStatsExportEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		exported = append(exported, exportEntry)
+	}
+	return exported, nil
+}
+
+func (r *gormStatsRepository) ImportEntries(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error) {
+	merged, keys, result := mergeImportEntries(entries, r.now())
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	// Rows are sorted so that concurrent writers lock them in the same order, as in flushBatch
+	sort.Strings(keys)
+	rows := make([]model.StatsEntry, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, *merged[key])
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns: statsEntryConflictColumns,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"hit_count":  gorm.Expr("stats_entries.hit_count + excluded.hit_count"),
+				"created_at": gorm.Expr("CASE WHEN excluded.created_at < stats_entries.created_at THEN excluded.created_at ELSE stats_entries.created_at END"),
+				"updated_at": gorm.Expr("CASE WHEN excluded.updated_at > stats_entries.updated_at THEN excluded.updated_at ELSE stats_entries.updated_at END"),
+			}),
+		}).CreateInBatches(&rows, flushChunkSize).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *gormStatsRepository) DeleteAll(ctx context.Context) (*model.StatsDeleteResult, error) {
+	return r.deleteEntries(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("1 = 1")
+	}, func(key string) bool {
+		return true
+	})
+}
+
+func (r *gormStatsRepository) DeleteEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error) {
+	// The buffered hits are keyed by parameter set: find the one of id
+	var entry model.StatsEntry
+	found := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&entry)
+	if found.Error != nil {
+		return nil, found.Error
+	}
+	if found.RowsAffected == 0 {
+		return &model.StatsDeleteResult{}, nil
+	}
+
+	key := entryKey(entry)
+	return r.deleteEntries(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ?", id)
+	}, func(pendingKey string) bool {
+		return pendingKey == key
+	})
+}
+
+func (r *gormStatsRepository) DeleteRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error) {
+	entry := newStatsEntry(request)
+	key := entryKey(entry)
+	return r.deleteEntries(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where(entryConditions(entry))
+	}, func(pendingKey string) bool {
+		return pendingKey == key
+	})
+}
+
+// entryConditions selects the stored entry of the parameter set of entry, by the columns of idx_params.
+// A map keeps the zero values (int1, int2 of the rules shape) that a struct condition would skip.
+func entryConditions(entry model.StatsEntry) map[string]interface{} {
+	return map[string]interface{}{
+		"int1": entry.Int1, "int2": entry.Int2, "limit": entry.Limit,
+		"str1": entry.Str1, "str2": entry.Str2, "rules_hash": entry.RulesHash,
+	}
+}
+
+// deleteEntries removes the entries selected by where, and their buckets, in one transaction. The buffered
+// hits of the parameter sets selected by matches are discarded rather than written: flushes wait for the
+// whole deletion, so none can bring the deleted entries back.
+func (r *gormStatsRepository) deleteEntries(ctx context.Context, where func(db *gorm.DB) *gorm.DB, matches func(key string) bool) (*model.StatsDeleteResult, error) {
+	var discarded map[string]*pendingHits
+	if r.flusherDone != nil {
+		r.flushMutex.Lock()
+		defer r.flushMutex.Unlock()
+
+		// On failure the discarded hits are lost too: the caller asked for them to be gone
+		discarded = r.discardPending(matches)
+	}
+
+	result := &model.StatsDeleteResult{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := where(tx.Model(&model.StatsEntry{})).
+			Select("COUNT(*) AS entries, COALESCE(SUM(hit_count), 0) AS hits").
+			Scan(result).Error
+		if err != nil {
+			return err
+		}
+
+		// Parameter sets only buffered, never written, are deleted entries too
+		if len(discarded) > 0 {
+			stored, err := countStoredEntries(tx, discarded)
+			if err != nil {
+				return err
+			}
+			result.Entries += int64(len(discarded)) - stored
+			for _, pending := range discarded {
+				result.Hits += pending.hits
+			}
+		}
+		if result.Entries == 0 {
+			return nil
+		}
+
+		entryIDs := where(tx.Model(&model.StatsEntry{})).Select("id")
+		if err := tx.Where("entry_id IN (?)", entryIDs).Delete(&model.StatsBucket{}).Error; err != nil {
+			return err
+		}
+		return where(tx).Delete(&model.StatsEntry{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Flush writes the hits buffered by write-behind batching. It does nothing without batching.
+func (r *gormStatsRepository) Flush(ctx context.Context) error {
+	if r.flusherDone == nil {
+		return nil
+	}
+	return r.flushBatch(ctx)
+}
+
+func (r *gormStatsRepository) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stop)
+
+		if r.flusherDone != nil {
+			<-r.flusherDone
+
+			ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			defer cancel()
+			err = r.flushBatch(ctx)
+		}
+	})
+	return err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"container/heap"
+	"container/list"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// evictionQueue orders the entries of the memory store by eviction priority, so that finding the
+// entry to evict does not scan the store. Every change of an entry's HitCount or UpdatedAt must be
+// reported with update.
+type evictionQueue interface {
+	push(key string, entry *model.StatsEntry)
+	update(key string)
+	remove(key string)
+	// victim returns the key of the entry to evict first other than skip, or false when there is none
+	victim(skip string) (string, bool)
+}
+
+func newEvictionQueue(policy string) evictionQueue {
+	if policy == config.EvictionPolicyLFU {
+		return &lfuQueue{items: make(map[string]*lfuItem)}
+	}
+	return &lruQueue{order: list.New(), elements: make(map[string]*list.Element)}
+}
+
+// lruBefore reports whether a should be evicted before b by the LRU policy: least recently requested
+// first, then lowest ID
+func lruBefore(a, b *model.StatsEntry) bool {
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// lfuBefore reports whether a should be evicted before b by the LFU policy: fewest hits first, then like LRU
+func lfuBefore(a, b *model.StatsEntry) bool {
+	if a.HitCount != b.HitCount {
+		return a.HitCount < b.HitCount
+	}
+	return lruBefore(a, b)
+}
+
+// lruQueue keeps the entries in a list, least recently requested first. A request moves its entry to
+// the back in O(1); only entries imported with an older updated_at are inserted further up.
+type lruQueue struct {
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *model.StatsEntry
+}
+
+func (q *lruQueue) push(key string, entry *model.StatsEntry) {
+	item := lruItem{key: key, entry: entry}
+	for element := q.order.Back(); element != nil; element = element.Prev() {
+		if !lruBefore(entry, element.Value.(lruItem).entry) {
+			q.elements[key] = q.order.InsertAfter(item, element)
+			return
+		}
+	}
+	q.elements[key] = q.order.PushFront(item)
+}
+
+func (q *lruQueue) update(key string) {
+	element, exists := q.elements[key]
+	if !exists {
+		return
+	}
+	q.order.Remove(element)
+	q.push(key, element.Value.(lruItem).entry)
+}
+
+func (q *lruQueue) remove(key string) {
+	if element, exists := q.elements[key]; exists {
+		q.order.Remove(element)
+		delete(q.elements, key)
+	}
+}
+
+func (q *lruQueue) victim(skip string) (string, bool) {
+	for element := q.order.Front(); element != nil; element = element.Next() {
+		if key := element.Value.(lruItem).key; key != skip {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// lfuQueue keeps the entries in a min-heap by lfuBefore: a request fixes its entry in O(log n)
+type lfuQueue struct {
+	heap  lfuHeap
+	items map[string]*lfuItem
+}
+
+type lfuItem struct {
+	key   string
+	entry *model.StatsEntry
+	index int // Position in the heap, maintained by lfuHeap
+}
+
+func (q *lfuQueue) push(key string, entry *model.StatsEntry) {
+	item := &lfuItem{key: key, entry: entry}
+	q.items[key] = item
+	heap.Push(&q.heap, item)
+}
+
+func (q *lfuQueue) update(key string) {
+	if item, exists := q.items[key]; exists {
+		heap.Fix(&q.heap, item.index)
+	}
+}
+
+func (q *lfuQueue) remove(key string) {
+	if item, exists := q.items[key]; exists {
+		heap.Remove(&q.heap, item.index)
+		delete(q.items, key)
+	}
+}
+
+func (q *lfuQueue) victim(skip string) (string, bool) {
+	if len(q.heap) == 0 {
+		return "", false
+	}
+	if q.heap[0].key != skip {
+		return q.heap[0].key, true
+	}
+
+	// The next one is a child of the root
+	var next *lfuItem
+	for _, i := range []int{1, 2} {
+		if i < len(q.heap) && (next == nil || q.heap.Less(i, next.index)) {
+			next = q.heap[i]
+		}
+	}
+	if next == nil {
+		return "", false
+	}
+	return next.key, true
+}
+
+// lfuHeap implements heap.Interface
+type lfuHeap []*lfuItem
+
+func (h lfuHeap) Len() int           { return len(h) }
+func (h lfuHeap) Less(i, j int) bool { return lfuBefore(h[i].entry, h[j].entry) }
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	item := x.(*lfuItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func TestEvictionQueue_MatchesScan(t *testing.T) {
+	tests := []struct {
+		policy string
+		before func(a, b *model.StatsEntry) bool
+	}{
+		{policy: config.EvictionPolicyLRU, before: lruBefore},
+		{policy: config.EvictionPolicyLFU, before: lfuBefore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			queue := newEvictionQueue(tt.policy)
+			entries := make(map[string]*model.StatsEntry)
+			random := rand.New(rand.NewSource(1))
+			base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			// The victim is the first entry by the policy, skipping the one given
+			scanVictim := func(skip string) (string, bool) {
+				var victimKey string
+				var victim *model.StatsEntry
+				for key, entry := range entries {
+					if key != skip && (victim == nil || tt.before(entry, victim)) {
+						victimKey, victim = key, entry
+					}
+				}
+				return victimKey, victim != nil
+			}
+
+			for i := 0; i < 2000; i++ {
+				key := fmt.Sprintf("key-%d", random.Intn(50))
+				entry, exists := entries[key]
+				switch {
+				case !exists:
+					// Imports may bring entries requested long ago
+					entry = &model.StatsEntry{ID: uint(i + 1), HitCount: int64(random.Intn(5) + 1), UpdatedAt: base.Add(time.Duration(random.Intn(i+1)) * time.Second)}
+					entries[key] = entry
+					queue.push(key, entry)
+				case random.Intn(5) == 0:
+					delete(entries, key)
+					queue.remove(key)
+				default:
+					entry.HitCount++
+					entry.UpdatedAt = base.Add(time.Duration(i) * time.Second)
+					queue.update(key)
+				}
+
+				for _, skip := range []string{"", key} {
+					expectedKey, expectedOK := scanVictim(skip)
+					victimKey, ok := queue.victim(skip)
+					require.Equal(t, expectedOK, ok)
+					require.Equal(t, expectedKey, victimKey, "step %d, skipping %q", i, skip)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsRepository_Memory_TopTrackedAcrossDeletions(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+
+	first := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	second := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	third := model.FizzBuzzRequest{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"}
+	ctx := context.Background()
+	for _, request := range []model.FizzBuzzRequest{first, first, first, second, second, third} {
+		require.NoError(t, repo.RecordRequest(ctx, request))
+	}
+	assert.Equal(t, generateKey(first), repo.topKey)
+
+	// Deleting the top entry makes the next one the top
+	_, err := repo.DeleteRequest(ctx, first)
+	require.NoError(t, err)
+	result, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, result.Request)
+
+	// Two more hits take the lead
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.RecordRequest(ctx, third))
+	}
+	result, err = repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, third, result.Request)
+
+	_, err = repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	result, err = repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
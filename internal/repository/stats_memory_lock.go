@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// errSnapshotLocked is returned by lockSnapshot when another process holds the lock
+var errSnapshotLocked = errors.New("held by another process")
+
+// snapshotLockPath is the lock file of the snapshot at path. The server holds it while it runs, and the
+// stats commands while they edit the snapshot, so that neither overwrites the other's changes.
+func snapshotLockPath(path string) string {
+	return path + ".lock"
+}
+
+// lockSnapshot takes the lock of the snapshot at path, until release is called
+func lockSnapshot(path string) (release func() error, err error) {
+	lockPath := snapshotLockPath(path)
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open stats snapshot lock %s: %w", lockPath, err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errSnapshotLocked) {
+			return nil, fmt.Errorf("stats snapshot %s is in use: lock %s is %w, a server or stats command using the same snapshot", path, lockPath, err)
+		}
+		return nil, fmt.Errorf("lock stats snapshot %s: %w", lockPath, err)
+	}
+	return file.Close, nil // Closing the file releases the lock
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package repository
+
+import "os"
+
+// lockFile does not lock on systems without flock: the snapshot is not protected there
+func lockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// memoryStatsRepository keeps stats in the process: they are lost on restart and not shared between instances
+type memoryStatsRepository struct {
+	bucketClock
+
+	entries map[string]*model.StatsEntry   // Bornée par maxEntries (éviction) et entryTTL (nettoyage périodique)
+	buckets map[string]map[time.Time]int64 // Hits per bucket start, keyed like entries
+	mutex   sync.RWMutex
+	nextID  uint   // IDs mirror the database primary key, used as the last tie-breaker
+	topKey  string // Key of the entry GetMostFrequent returns, "" when the store is empty
+
+	// Protection contre les fuites mémoire
+	maxEntries     int           // Limite max d'entrées, 0 = pas de limite
+	evictionPolicy string        // config.EvictionPolicyLRU ou config.EvictionPolicyLFU
+	eviction       evictionQueue // Entrées dans l'ordre d'éviction de evictionPolicy
+	entryTTL       time.Duration // Les entrées non demandées depuis entryTTL sont supprimées, 0 = jamais
+	evictions      int64         // Entrées supprimées parce que maxEntries était atteint
+	expirations    int64         // Entrées supprimées par le nettoyage TTL
+	stop           chan struct{} // Fermé par Close() pour arrêter le nettoyage et les snapshots périodiques
+	closeOnce      sync.Once
+
+	// Persistance entre redémarrages, voir stats_memory_snapshot.go
+	snapshotPath   string        // Fichier de snapshot, vide = désactivé
+	unlockSnapshot func() error  // Libère le verrou du snapshot, pris jusqu'à Close()
+	snapshotErrors int64         // Sauvegardes échouées
+	snapshotDone   chan struct{} // Fermé quand la goroutine de snapshot s'est arrêtée, nil sans snapshot
+}
+
+func newMemoryStatsRepository(cfg *config.Config) (*memoryStatsRepository, error) {
+	repo := &memoryStatsRepository{
+		bucketClock: newBucketClock(cfg),
+
+		entries: make(map[string]*model.StatsEntry),
+		buckets: make(map[string]map[time.Time]int64),
+
+		maxEntries:     cfg.Stats.MemoryMaxEntries,
+		evictionPolicy: cfg.Stats.MemoryEvictionPolicy,
+		eviction:       newEvictionQueue(cfg.Stats.MemoryEvictionPolicy),
+		entryTTL:       cfg.Stats.MemoryEntryTTL,
+		stop:           make(chan struct{}),
+
+		snapshotPath: cfg.Stats.MemorySnapshotPath,
+	}
+
+	// Restaurer les stats du dernier arrêt, puis les sauvegarder périodiquement et à l'arrêt
+	if repo.snapshotPath != "" {
+		unlock, err := lockSnapshot(repo.snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		repo.unlockSnapshot = unlock
+
+		if err := repo.loadSnapshot(); err != nil {
+			// Le prochain snapshot écraserait le fichier illisible : le mettre de côté, ou refuser de démarrer
+			corruptPath, renameErr := moveSnapshotAside(repo.snapshotPath, repo.now())
+			if renameErr != nil {
+				return nil, errors.Join(err, unlock(), fmt.Errorf("move unreadable stats snapshot aside: %w", renameErr))
+			}
+			log.Errorf("Failed to restore stats snapshot, moved it to %s and starting empty: %v", corruptPath, err)
+		}
+		repo.snapshotDone = make(chan struct{})
+		go repo.startPeriodicSnapshot(cfg.Stats.MemorySnapshotInterval)
+	}
+
+	// Démarrer le nettoyage périodique, arrêté par Close() à l'arrêt de l'application
+	if (repo.entryTTL > 0 || repo.bucketRetention > 0) && cfg.Stats.MemoryCleanupInterval > 0 {
+		go repo.startPeriodicCleanup(cfg.Stats.MemoryCleanupInterval)
+	}
+
+	return repo, nil
+}
+
+func (r *memoryStatsRepository) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) error {
+	r.mutex.Lock()         //Exclusif, bloque TOUT (lecteurs + écrivains): L'enregistrement des stats bloque temporairement les lectures
+	defer r.mutex.Unlock() // S'exécute automatiquement à la fin, même si une erreur survient, unlock() sera appelé
+
+	now := r.now()
+	key := generateKey(request)
+	if entry, exists := r.entries[key]; exists {
+		entry.HitCount++
+		entry.UpdatedAt = now
+		r.entryChangedLocked(key)
+	} else {
+		// Vérifier la limite avant d'ajouter une nouvelle entrée
+		if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+			r.evictEntryLocked()
+		}
+
+		r.nextID++
+		entry := newStatsEntry(request)
+		entry.ID = r.nextID
+		entry.HitCount = 1
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+		r.addEntryLocked(key, &entry)
+	}
+
+	buckets := r.buckets[key]
+	start := r.bucketStart(now)
+	if _, exists := buckets[start]; !exists {
+		// Pruning only when a bucket is opened keeps it to once per bucket and entry
+		if cutoff, prune := r.retentionCutoff(now); prune {
+			for bucketStart := range buckets {
+				if bucketStart.Before(cutoff) {
+					delete(buckets, bucketStart)
+				}
+			}
+		}
+	}
+	buckets[start]++
+	return nil
+}
+
+// evictEntryLocked removes one entry according to the eviction policy, never the current top entry
+// so that GET /stats keeps answering the same. It returns false when only the top entry is left.
+// Callers must hold mutex for writing.
+func (r *memoryStatsRepository) evictEntryLocked() bool {
+	key, ok := r.eviction.victim(r.topKey)
+	if !ok {
+		return false
+	}
+	r.removeEntryLocked(key)
+	r.evictions++
+	return true
+}
+
+// addEntryLocked stores a new entry under key. Callers must hold mutex for writing.
+func (r *memoryStatsRepository) addEntryLocked(key string, entry *model.StatsEntry) {
+	r.entries[key] = entry
+	r.buckets[key] = make(map[time.Time]int64)
+	r.eviction.push(key, entry)
+	r.promoteLocked(key)
+}
+
+// entryChangedLocked reorders the entry stored under key after its hits or times changed.
+// Callers must hold mutex for writing.
+func (r *memoryStatsRepository) entryChangedLocked(key string) {
+	r.eviction.update(key)
+	r.promoteLocked(key)
+}
+
+// removeEntryLocked deletes the entry stored under key and its buckets. Callers must hold mutex for writing.
+func (r *memoryStatsRepository) removeEntryLocked(key string) {
+	delete(r.entries, key)
+	delete(r.buckets, key)
+	r.eviction.remove(key)
+	if key == r.topKey {
+		r.topKey = r.findTopKeyLocked()
+	}
+}
+
+// promoteLocked makes the entry stored under key the top entry when it now ranks first. Hits only
+// grow and first request times only move back, so entries other than key keep their order.
+func (r *memoryStatsRepository) promoteLocked(key string) {
+	if r.topKey == "" || ranksBefore(r.entries[key], r.entries[r.topKey]) {
+		r.topKey = key
+	}
+}
+
+// findTopKeyLocked scans the store for the entry GetMostFrequent returns, or "" when the store is
+// empty. It only runs when the top entry is deleted.
+func (r *memoryStatsRepository) findTopKeyLocked() string {
+	var topKey string
+	var top *model.StatsEntry
+	for key, entry := range r.entries {
+		if top == nil || ranksBefore(entry, top) {
+			topKey, top = key, entry
+		}
+	}
+	return topKey
+}
+
+func (r *memoryStatsRepository) startPeriodicCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.cleanupExpiredEntries()
+			r.pruneExpiredBuckets()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// cleanupExpiredEntries removes entries not requested for entryTTL, except the current top entry
+func (r *memoryStatsRepository) cleanupExpiredEntries() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.now()
+	for key, entry := range r.entries {
+		if key != r.topKey && now.Sub(entry.UpdatedAt) > r.entryTTL {
+			r.removeEntryLocked(key) // Supprimer les entrées expirées
+			r.expirations++
+		}
+	}
+}
+
+// pruneExpiredBuckets removes the buckets older than the retention of every entry, including
+// those no longer requested, which RecordRequest never prunes
+func (r *memoryStatsRepository) pruneExpiredBuckets() {
+	cutoff, prune := r.retentionCutoff(r.now())
+	if !prune {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, buckets := range r.buckets {
+		for bucketStart := range buckets {
+			if bucketStart.Before(cutoff) {
+				delete(buckets, bucketStart)
+			}
+		}
+	}
+}
+
+func (r *memoryStatsRepository) GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	metrics := &model.StatsStoreMetrics{
+		Storage:        "memory",
+		Entries:        int64(len(r.entries)),
+		MaxEntries:     r.maxEntries,
+		EvictionPolicy: r.evictionPolicy,
+		Evictions:      r.evictions,
+		Expirations:    r.expirations,
+		SnapshotErrors: r.snapshotErrors,
+	}
+	if r.entryTTL > 0 {
+		metrics.EntryTTL = r.entryTTL.String()
+	}
+	return metrics, nil
+}
+
+func (r *memoryStatsRepository) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stop)
+
+		if r.snapshotDone != nil {
+			<-r.snapshotDone
+			err = errors.Join(r.saveSnapshot(), r.unlockSnapshot())
+		}
+	})
+	return err
+}
+
+func (r *memoryStatsRepository) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
+	r.mutex.RLock() //Partagé entre lecteurs, mais bloqué par écrivains, plusieurs utilisateurs peuvent consulter /stats en même temps
+	defer r.mutex.RUnlock()
+
+	mostFrequent, exists := r.entries[r.topKey]
+	if !exists {
+		return nil, nil
+	}
+
+	return toStatsResponse(*mostFrequent)
+}
+
+func (r *memoryStatsRepository) GetTop(ctx context.Context, n, offset int) ([]model.StatsResponse, int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := make([]*model.StatsEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return ranksBefore(entries[i], entries[j])
+	})
+
+	total := int64(len(entries))
+	if offset >= len(entries) {
+		return []model.StatsResponse{}, total, nil
+	}
+	entries = entries[offset:min(offset+n, len(entries))]
+
+	top := make([]model.StatsResponse, 0, len(entries))
+	for _, entry := range entries {
+		response, err := toStatsResponse(*entry)
+		if err != nil {
+			return nil, 0, err
+		}
+		top = append(top, *response)
+	}
+	return top, total, nil
+}
+
+func (r *memoryStatsRepository) GetMostFrequentBetween(ctx context.Context, since, until time.Time) (*model.StatsResponse, error) {
+	since = r.bucketStart(since)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var mostFrequent *model.StatsEntry
+	var mostHits int64
+	for key, entry := range r.entries {
+		var hits int64
+		for bucketStart, count := range r.buckets[key] {
+			if !bucketStart.Before(since) && bucketStart.Before(until) {
+				hits += count
+			}
+		}
+		if hits == 0 {
+			continue
+		}
+		if mostFrequent == nil || hits > mostHits || (hits == mostHits && createdBefore(entry, mostFrequent)) {
+			mostFrequent, mostHits = entry, hits
+		}
+	}
+
+	if mostFrequent == nil {
+		return nil, nil
+	}
+
+	response, err := toStatsResponse(*mostFrequent)
+	if err != nil {
+		return nil, err
+	}
+	response.HitCount = mostHits
+	return response, nil
+}
+
+func (r *memoryStatsRepository) ExportEntries(ctx context.Context) ([]model.StatsExportEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := make([]*model.StatsEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return ranksBefore(entries[i], entries[j])
+	})
+
+	exported := make([]model.StatsExportEntry, 0, len(entries))
+	for _, entry := range entries {
+		exportEntry, err := toStatsExportEntry(*entry)
+		if err != nil {
+			return nil, err
+		}
+		exported = append(exported, exportEntry)
+	}
+	return exported, nil
+}
+
+func (r *memoryStatsRepository) ImportEntries(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	merged, keys, result := mergeImportEntries(entries, r.now())
+	for _, key := range keys {
+		if entry, exists := r.entries[key]; exists {
+			mergeStatsEntry(entry, *merged[key])
+			r.entryChangedLocked(key)
+			continue
+		}
+
+		// Comme RecordRequest : l'import respecte maxEntries
+		if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+			r.evictEntryLocked()
+		}
+
+		r.nextID++
+		entry := merged[key]
+		entry.ID = r.nextID
+		r.addEntryLocked(key, entry)
+	}
+	return result, nil
+}
+
+func (r *memoryStatsRepository) DeleteAll(ctx context.Context) (*model.StatsDeleteResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := &model.StatsDeleteResult{Entries: int64(len(r.entries))}
+	for _, entry := range r.entries {
+		result.Hits += entry.HitCount
+	}
+
+	// nextID n'est pas remis à zéro : un ID supprimé ne désigne jamais une nouvelle entrée
+	r.entries = make(map[string]*model.StatsEntry)
+	r.buckets = make(map[string]map[time.Time]int64)
+	r.eviction = newEvictionQueue(r.evictionPolicy)
+	r.topKey = ""
+	return result, nil
+}
+
+func (r *memoryStatsRepository) DeleteEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key, entry := range r.entries {
+		if entry.ID == id {
+			return r.deleteKeyLocked(key), nil
+		}
+	}
+	return &model.StatsDeleteResult{}, nil
+}
+
+func (r *memoryStatsRepository) DeleteRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.deleteKeyLocked(generateKey(request)), nil
+}
+
+// deleteKeyLocked removes the entry stored under key, if any. Callers must hold mutex for writing.
+func (r *memoryStatsRepository) deleteKeyLocked(key string) *model.StatsDeleteResult {
+	entry, exists := r.entries[key]
+	if !exists {
+		return &model.StatsDeleteResult{}
+	}
+
+	r.removeEntryLocked(key)
+	return &model.StatsDeleteResult{Entries: 1, Hits: entry.HitCount}
+}
+
+// ranksBefore is the in-memory equivalent of statsOrder
+func ranksBefore(a, b *model.StatsEntry) bool {
+	if a.HitCount != b.HitCount {
+		return a.HitCount > b.HitCount
+	}
+	return createdBefore(a, b)
+}
+
+// createdBefore breaks ties between equal hit counts like statsOrder: oldest first, then lowest ID.
+// Creation times are compared to the microsecond, the precision PostgreSQL stores.
+func createdBefore(a, b *model.StatsEntry) bool {
+	aCreated, bCreated := a.CreatedAt.Truncate(time.Microsecond), b.CreatedAt.Truncate(time.Microsecond)
+	if !aCreated.Equal(bCreated) {
+		return aCreated.Before(bCreated)
+	}
+	return a.ID < b.ID
+}
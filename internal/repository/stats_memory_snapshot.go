@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// memorySnapshotVersion is bumped when the snapshot format changes incompatibly
+const memorySnapshotVersion = 1
+
+// memorySnapshot is the JSON file the memory store is saved to
+type memorySnapshot struct {
+	Version int                   `json:"version"`
+	SavedAt time.Time             `json:"saved_at"`
+	NextID  uint                  `json:"next_id"`
+	Entries []memorySnapshotEntry `json:"entries"`
+}
+
+// memorySnapshotEntry is a model.StatsEntry, whose fields are hidden from JSON, with its buckets
+type memorySnapshotEntry struct {
+	ID        uint                `json:"id"`
+	Int1      int                 `json:"int1"`
+	Int2      int                 `json:"int2"`
+	Limit     int                 `json:"limit"`
+	Str1      string              `json:"str1"`
+	Str2      string              `json:"str2"`
+	Rules     string              `json:"rules,omitempty"`
+	HitCount  int64               `json:"hit_count"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Buckets   map[time.Time]int64 `json:"buckets,omitempty"`
+}
+
+// startPeriodicSnapshot saves the store every interval until Close, or only at Close when interval is 0
+func (r *memoryStatsRepository) startPeriodicSnapshot(interval time.Duration) {
+	defer close(r.snapshotDone)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			if err := r.saveSnapshot(); err != nil {
+				log.Errorf("Failed to save stats snapshot, retrying at next snapshot: %v", err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// saveSnapshot writes the store to snapshotPath. The file is replaced by a rename, so that a crash
+// during the write leaves the previous snapshot intact.
+func (r *memoryStatsRepository) saveSnapshot() error {
+	r.mutex.RLock()
+	snapshot := memorySnapshot{
+		Version: memorySnapshotVersion,
+		SavedAt: r.now().UTC(),
+		NextID:  r.nextID,
+		Entries: make([]memorySnapshotEntry, 0, len(r.entries)),
+	}
+	for key, entry := range r.entries {
+		buckets := make(map[time.Time]int64, len(r.buckets[key]))
+		for bucketStart, hits := range r.buckets[key] {
+			buckets[bucketStart] = hits
+		}
+		snapshot.Entries = append(snapshot.Entries, memorySnapshotEntry{
+			ID:        entry.ID,
+			Int1:      entry.Int1,
+			Int2:      entry.Int2,
+			Limit:     entry.Limit,
+			Str1:      entry.Str1,
+			Str2:      entry.Str2,
+			Rules:     entry.Rules,
+			HitCount:  entry.HitCount,
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+			Buckets:   buckets,
+		})
+	}
+	r.mutex.RUnlock()
+
+	err := writeFileAtomic(r.snapshotPath, snapshot)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil {
+		r.snapshotErrors++
+	}
+	return err
+}
+
+// writeFileAtomic encodes v as JSON to a temporary file next to path, then renames it to path
+func writeFileAtomic(path string, v interface{}) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = json.NewEncoder(tmp).Encode(v); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// moveSnapshotAside renames the snapshot at path to <path>.corrupt-<timestamp>, so that an unreadable
+// snapshot can be inspected or repaired instead of being overwritten by the next save
+func moveSnapshotAside(path string, now time.Time) (string, error) {
+	corruptPath := path + ".corrupt-" + now.UTC().Format("20060102T150405Z")
+	if err := os.Rename(path, corruptPath); err != nil {
+		return "", err
+	}
+	return corruptPath, nil
+}
+
+// loadSnapshot restores the store saved at snapshotPath. A missing file leaves the store empty.
+// Entries beyond maxEntries, when it was lowered since the snapshot, are evicted as usual.
+// Entries saved twice for the same parameters are merged.
+func (r *memoryStatsRepository) loadSnapshot() error {
+	data, err := os.ReadFile(r.snapshotPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("decode stats snapshot %s: %w", r.snapshotPath, err)
+	}
+	if snapshot.Version != memorySnapshotVersion {
+		return fmt.Errorf("stats snapshot %s has version %d, expected %d", r.snapshotPath, snapshot.Version, memorySnapshotVersion)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Least recently requested first: each entry then joins the back of the LRU list
+	sort.Slice(snapshot.Entries, func(i, j int) bool {
+		a, b := snapshot.Entries[i], snapshot.Entries[j]
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	r.nextID = snapshot.NextID
+	for _, saved := range snapshot.Entries {
+		entry := &model.StatsEntry{
+			ID:        saved.ID,
+			Int1:      saved.Int1,
+			Int2:      saved.Int2,
+			Limit:     saved.Limit,
+			Str1:      saved.Str1,
+			Str2:      saved.Str2,
+			Rules:     saved.Rules,
+			RulesHash: model.HashRules(saved.Rules),
+			HitCount:  saved.HitCount,
+			CreatedAt: saved.CreatedAt,
+			UpdatedAt: saved.UpdatedAt,
+		}
+		key := entryKey(*entry)
+		if existing, exists := r.entries[key]; exists {
+			// A hand-edited or imported file may list a parameter set twice: its hits add up
+			mergeStatsEntry(existing, *entry)
+			if entry.ID < existing.ID {
+				existing.ID = entry.ID
+			}
+			r.entryChangedLocked(key)
+		} else {
+			r.addEntryLocked(key, entry)
+		}
+		for bucketStart, hits := range saved.Buckets {
+			r.buckets[key][bucketStart.UTC()] += hits
+		}
+		if entry.ID > r.nextID {
+			r.nextID = entry.ID
+		}
+	}
+
+	for r.maxEntries > 0 && len(r.entries) > r.maxEntries {
+		if !r.evictEntryLocked() {
+			break
+		}
+	}
+	return nil
+}
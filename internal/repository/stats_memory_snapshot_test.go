@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func newSnapshotConfig(path string) *config.Config {
+	return &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity:  time.Hour,
+			MemorySnapshotPath: path,
+		},
+	}
+}
+
+func TestStatsRepository_Memory_SnapshotRestoredAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	base := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	threeRules := model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "a"}, {Divisor: 5, Word: "b"}, {Divisor: 7, Word: "c"}}}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	repo := newTestStatsRepository(t, nil, newSnapshotConfig(path)).(*memoryStatsRepository)
+	repo.now = func() time.Time { return base }
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.RecordRequest(context.Background(), fizzBuzz))
+	}
+	require.NoError(t, repo.RecordRequest(context.Background(), threeRules))
+	repo.now = func() time.Time { return base.Add(2 * time.Hour) }
+	require.NoError(t, repo.RecordRequest(context.Background(), threeRules))
+
+	// Close saves the store
+	require.NoError(t, repo.Close())
+	require.FileExists(t, path)
+
+	restored := newTestStatsRepository(t, nil, newSnapshotConfig(path)).(*memoryStatsRepository)
+	defer restored.Close()
+
+	top, total, err := restored.GetTop(context.Background(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []model.StatsResponse{
+		{ID: 1, Request: fizzBuzz, HitCount: 3},
+		{ID: 2, Request: threeRules, HitCount: 2},
+	}, top)
+
+	// Buckets are restored too
+	result, err := restored.GetMostFrequentBetween(context.Background(), base.Add(time.Hour), base.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{ID: 2, Request: threeRules, HitCount: 1}, result)
+
+	// New entries get IDs after the restored ones, so ties still favour the older entry
+	restored.now = func() time.Time { return base }
+	for i := 0; i < 3; i++ {
+		require.NoError(t, restored.RecordRequest(context.Background(), fooBar))
+	}
+	assert.Equal(t, uint(3), restored.entries[generateKey(fooBar)].ID)
+
+	result, err = restored.GetMostFrequent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, fizzBuzz, result.Request)
+}
+
+func TestStatsRepository_Memory_PeriodicSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stats.json")
+
+	cfg := newSnapshotConfig(path)
+	cfg.Stats.MemorySnapshotInterval = 5 * time.Millisecond
+	repo := newTestStatsRepository(t, nil, cfg)
+
+	require.NoError(t, repo.RecordRequest(context.Background(), model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}))
+
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && len(data) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	// Close waits for the save in progress, if any
+	require.NoError(t, repo.Close())
+
+	// Temporary files are renamed over the snapshot, none are left behind
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, file := range files {
+		assert.Contains(t, []string{"stats.json", "stats.json.lock"}, file.Name())
+	}
+}
+
+func TestStatsRepository_Memory_SnapshotMissingOrInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string // Empty: no file
+	}{
+		{name: "missing file"},
+		{name: "invalid JSON", contents: "{not json"},
+		{name: "unknown version", contents: `{"version": 99, "entries": [{"id": 1, "hit_count": 5}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stats.json")
+			if tt.contents != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o644))
+			}
+
+			// The repository starts empty instead of failing the startup
+			repo := newTestStatsRepository(t, nil, newSnapshotConfig(path))
+			defer repo.Close()
+
+			result, err := repo.GetMostFrequent(context.Background())
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			// An unreadable file is moved aside, out of reach of the next save
+			corrupt, err := filepath.Glob(path + ".corrupt-*")
+			require.NoError(t, err)
+			if tt.contents == "" {
+				assert.Empty(t, corrupt)
+				return
+			}
+			require.Len(t, corrupt, 1)
+			data, err := os.ReadFile(corrupt[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.contents, string(data))
+			assert.NoFileExists(t, path)
+		})
+	}
+}
+
+func TestStatsRepository_Memory_SnapshotRestoreAppliesMaxEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	requests := []model.FizzBuzzRequest{
+		{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"},
+		{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"},
+	}
+
+	repo := newTestStatsRepository(t, nil, newSnapshotConfig(path)).(*memoryStatsRepository)
+	for i, request := range requests {
+		repo.now = func() time.Time { return base.Add(time.Duration(i) * time.Minute) }
+		require.NoError(t, repo.RecordRequest(context.Background(), request))
+	}
+	require.NoError(t, repo.Close())
+
+	// The limit was lowered since the snapshot: the least recently requested entry is evicted
+	cfg := newSnapshotConfig(path)
+	cfg.Stats.MemoryMaxEntries = 2
+	cfg.Stats.MemoryEvictionPolicy = config.EvictionPolicyLRU
+	restored := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+	defer restored.Close()
+
+	assert.Len(t, restored.entries, 2)
+	assert.Contains(t, restored.entries, generateKey(requests[0])) // Top entry, never evicted
+	assert.Contains(t, restored.entries, generateKey(requests[2]))
+
+	metrics, err := restored.GetStoreMetrics(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.Evictions)
+}
+
+func TestStatsRepository_Memory_SnapshotMergesDuplicateEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	// fizzBuzz is listed twice, as in a hand-edited file: together it outranks fooBar
+	data, err := json.Marshal(memorySnapshot{
+		Version: memorySnapshotVersion,
+		NextID:  3,
+		Entries: []memorySnapshotEntry{
+			{ID: 1, Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 2, CreatedAt: base, UpdatedAt: base, Buckets: map[time.Time]int64{base: 2}},
+			{ID: 2, Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar", HitCount: 3, CreatedAt: base, UpdatedAt: base, Buckets: map[time.Time]int64{base: 3}},
+			{ID: 3, Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", HitCount: 2, CreatedAt: base.Add(time.Hour), UpdatedAt: base.Add(time.Hour), Buckets: map[time.Time]int64{base: 1, base.Add(time.Hour): 1}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	repo := newTestStatsRepository(t, nil, newSnapshotConfig(path)).(*memoryStatsRepository)
+	defer repo.Close()
+
+	assert.Len(t, repo.entries, 2)
+	entry := repo.entries[generateKey(fizzBuzz)]
+	require.NotNil(t, entry)
+	assert.Equal(t, uint(1), entry.ID)
+	assert.Equal(t, int64(4), entry.HitCount)
+	assert.True(t, entry.CreatedAt.Equal(base))
+	assert.True(t, entry.UpdatedAt.Equal(base.Add(time.Hour)))
+	assert.Equal(t, map[time.Time]int64{base: 3, base.Add(time.Hour): 1}, repo.buckets[generateKey(fizzBuzz)])
+
+	result, err := repo.GetMostFrequent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{ID: 1, Request: fizzBuzz, HitCount: 4}, result)
+
+	top, _, err := repo.GetTop(context.Background(), 2, 0)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, fooBar, top[1].Request)
+}
+
+func TestStatsRepository_Memory_SnapshotWriteError(t *testing.T) {
+	// The directory of the snapshot is removed while the server runs: saves fail and are counted
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "stats.json")
+
+	repo := newTestStatsRepository(t, nil, newSnapshotConfig(path))
+	require.NoError(t, os.RemoveAll(dir))
+
+	assert.Error(t, repo.Close())
+
+	metrics, err := repo.GetStoreMetrics(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.SnapshotErrors)
+}
+
+func TestStatsRepository_Memory_SnapshotLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	repo := newTestStatsRepository(t, nil, newSnapshotConfig(path))
+
+	// A second process on the same snapshot, such as a stats command while the server runs, is refused
+	_, err := NewStatsRepository(nil, newSnapshotConfig(path))
+	assert.ErrorContains(t, err, "stats.json.lock")
+
+	// Close releases the lock
+	require.NoError(t, repo.Close())
+	other := newTestStatsRepository(t, nil, newSnapshotConfig(path))
+	assert.NoError(t, other.Close())
+}
@@ -2,8 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"sync"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,174 +12,207 @@ import (
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 )
 
+// IStatsRepository is implemented by every stats backend. repositorytest.RunStatsRepositorySuite
+// checks that a backend behaves like the others.
 type IStatsRepository interface {
 	RecordRequest(ctx context.Context, request model.FizzBuzzRequest) error
 	GetMostFrequent(ctx context.Context) (*model.StatsResponse, error)
+	// GetTop returns up to n entries starting at offset, in statsOrder, along with the total number of entries.
+	GetTop(ctx context.Context, n, offset int) ([]model.StatsResponse, int64, error)
+	// GetMostFrequentBetween is GetMostFrequent restricted to the hits counted in buckets starting
+	// between since (rounded down to its bucket) and until (excluded). It returns nil when there are none.
+	GetMostFrequentBetween(ctx context.Context, since, until time.Time) (*model.StatsResponse, error)
+	// GetStoreMetrics reports the size of the store and, in memory mode, how many entries were evicted or expired.
+	GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error)
+	// ExportEntries returns every entry in statsOrder, including the hits still buffered before a write.
+	ExportEntries(ctx context.Context) ([]model.StatsExportEntry, error)
+	// ImportEntries adds entries to the store. The hits of a parameter set already stored are added to
+	// its count, keeping the earliest created_at and latest updated_at. Imported hits only count in
+	// all-time stats: they belong to no time bucket.
+	ImportEntries(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error)
+	// DeleteAll removes every entry and its buckets. The hits still buffered before a write are
+	// discarded with them, but hits recorded while the deletion runs, such as those still queued by
+	// the stats recorder, may land afterwards and recreate their entries.
+	DeleteAll(ctx context.Context) (*model.StatsDeleteResult, error)
+	// DeleteEntry removes the entry with id and its buckets. The result counts no entry when there is none.
+	DeleteEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error)
+	// DeleteRequest removes the entry of the parameter set of request and its buckets, like DeleteEntry.
+	// Both discard the buffered hits of the deleted entry like DeleteAll.
+	DeleteRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error)
+	// Close stops background work (memory cleanup, batch flushes) and writes the hits still buffered.
+	// Reads keep working afterwards, but hits recorded after Close may never be written.
+	Close() error
 }
 
-type statsRepository struct {
-	db        *gorm.DB
-	memStats  map[string]*model.StatsEntry // ⚠️ PROBLÈME: Map grandit indéfiniment = fuite mémoire
-	memMutex  sync.RWMutex
-	useMemory bool
-
-	// AMÉLIORATION: Ajouter ces champs pour éviter les fuites mémoire
-	// maxEntries int                    // Limite max d'entrées (ex: 10000)
-	// cleanupTicker *time.Ticker        // Nettoyage périodique des anciennes entrées
-	// entryTTL time.Duration            // TTL pour expirer les entrées (ex: 24h)
+// IStatsFlusher is implemented by backends that buffer hits before writing them.
+// Flush writes the buffered hits, so that reads see every hit recorded before the call.
+type IStatsFlusher interface {
+	Flush(ctx context.Context) error
 }
 
-func NewStatsRepository(database *gorm.DB, cfg *config.Config) IStatsRepository {
-	useMemory := cfg.Database.StatsStorage == "memory"
-	return &statsRepository{
-		db:        database,
-		memStats:  make(map[string]*model.StatsEntry),
-		useMemory: useMemory,
+// IStatsClock is implemented by backends that bucket hits by time.
+// SetNow replaces the clock timestamping hits, so that tests can record hits at chosen times.
+type IStatsClock interface {
+	SetNow(now func() time.Time)
+}
 
-		// AMÉLIORATION: Initialiser la protection contre les fuites mémoire
-		// maxEntries:    10000,                    // Limite à 10k entrées
-		// entryTTL:      24 * time.Hour,           // Expirer après 24h
-		// cleanupTicker: time.NewTicker(1 * time.Hour), // Cleanup toutes les heures
+// statsOrder ranks entries by hits, breaking ties by age then ID so that every backend returns the same order
+const statsOrder = "hit_count DESC, created_at ASC, id ASC"
+
+// NewStatsRepository returns the backend selected by cfg.Database.StatsStorage: memory, or an SQL
+// database (postgres, sqlite) reached through database. In memory mode with a snapshot, it fails when
+// another process holds the snapshot lock.
+func NewStatsRepository(database *gorm.DB, cfg *config.Config) (IStatsRepository, error) {
+	if cfg.Database.StatsStorage == "memory" {
+		repo, err := newMemoryStatsRepository(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
 	}
+	return newGormStatsRepository(database, cfg), nil
+}
 
-	// AMÉLIORATION: Démarrer le nettoyage périodique
-	// if useMemory {
-	//     go repo.startPeriodicCleanup()
-	// }
+// bucketClock timestamps hits and maps them to the time buckets of windowed stats
+type bucketClock struct {
+	bucketSize      time.Duration
+	bucketRetention time.Duration
+	now             func() time.Time
 }
 
-func (r *statsRepository) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) error {
-	if r.useMemory {
-		return r.recordInMemory(request)
+func newBucketClock(cfg *config.Config) bucketClock {
+	bucketSize := cfg.Stats.BucketGranularity
+	if bucketSize <= 0 {
+		bucketSize = time.Hour
+	}
+	return bucketClock{
+		bucketSize:      bucketSize,
+		bucketRetention: cfg.Stats.BucketRetention,
+		now:             time.Now,
 	}
-	return r.recordInDatabase(ctx, request)
 }
 
-func (r *statsRepository) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
-	if r.useMemory {
-		return r.getMostFrequentFromMemory()
+// SetNow replaces the clock timestamping hits. It must not be called while hits are recorded.
+func (c *bucketClock) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// bucketStart returns the start of the bucket containing t, in UTC
+func (c *bucketClock) bucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(c.bucketSize)
+}
+
+// retentionCutoff returns the start of the oldest bucket to keep, and false when buckets are kept forever
+func (c *bucketClock) retentionCutoff(now time.Time) (time.Time, bool) {
+	if c.bucketRetention <= 0 {
+		return time.Time{}, false
 	}
-	return r.getMostFrequentFromDatabase(ctx)
-}
-
-func (r *statsRepository) recordInMemory(request model.FizzBuzzRequest) error {
-	r.memMutex.Lock()         //Exclusif, bloque TOUT (lecteurs + écrivains): L'enregistrement des stats bloque temporairement les lectures
-	defer r.memMutex.Unlock() // S'exécute automatiquement à la fin, même si une erreur survient, unlock() sera appelé
-
-	// AMÉLIORATION: Vérifier la limite avant d'ajouter une nouvelle entrée
-	// if len(r.memStats) >= r.maxEntries {
-	//     r.evictOldestEntry() // Supprimer la plus ancienne entrée
-	// }
-
-	key := r.generateKey(request)
-	if entry, exists := r.memStats[key]; exists {
-		entry.HitCount++
-		entry.UpdatedAt = time.Now()
-	} else {
-		// PROBLÈME: Nouvelle entrée sans limite = fuite mémoire potentielle
-		r.memStats[key] = &model.StatsEntry{
-			Int1:      request.Int1,
-			Int2:      request.Int2,
-			Limit:     request.Limit,
-			Str1:      request.Str1,
-			Str2:      request.Str2,
-			HitCount:  1,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+	return c.bucketStart(now.Add(-c.bucketRetention)), true
+}
+
+// generateKey identifies a parameter set: requests with the same key share a stats entry
+func generateKey(request model.FizzBuzzRequest) string {
+	return entryKey(newStatsEntry(request))
+}
+
+// entryKey is generateKey for an entry already in its canonical form
+func entryKey(entry model.StatsEntry) string {
+	return fmt.Sprintf("%d_%d_%d_%s_%s_%s", entry.Int1, entry.Int2, entry.Limit, entry.Str1, entry.Str2, entry.Rules)
+}
+
+// newStatsEntry maps a request to its canonical stats row: two-rule requests (legacy or rules form)
+// fill the int1/int2/str1/str2 columns, any other rule count is stored JSON-encoded in Rules.
+func newStatsEntry(request model.FizzBuzzRequest) model.StatsEntry {
+	rules := request.EffectiveRules()
+	if len(rules) == 2 {
+		return model.StatsEntry{
+			Int1:  rules[0].Divisor,
+			Int2:  rules[1].Divisor,
+			Limit: request.Limit,
+			Str1:  rules[0].Word,
+			Str2:  rules[1].Word,
 		}
 	}
-	return nil
-}
-
-// 💡 AMÉLIORATION: Fonctions pour éviter les fuites mémoire
-// func (r *statsRepository) cleanupExpiredEntries() {
-//     r.memMutex.Lock()
-//     defer r.memMutex.Unlock()
-//
-//     now := time.Now()
-//     for key, entry := range r.memStats {
-//         if now.Sub(entry.UpdatedAt) > r.entryTTL {
-//             delete(r.memStats, key) // Supprimer les entrées expirées
-//         }
-//     }
-// }
-
-func (r *statsRepository) getMostFrequentFromMemory() (*model.StatsResponse, error) {
-	r.memMutex.RLock() //Partagé entre lecteurs, mais bloqué par écrivains, plusieurs utilisateurs peuvent consulter /stats en même temps
-	defer r.memMutex.RUnlock()
-
-	var mostFrequent *model.StatsEntry
-	for _, entry := range r.memStats {
-		if mostFrequent == nil || entry.HitCount > mostFrequent.HitCount {
-			mostFrequent = entry
-		}
+
+	encoded, _ := json.Marshal(rules) // []model.Rule always marshals
+	return model.StatsEntry{
+		Limit:     request.Limit,
+		Rules:     string(encoded),
+		RulesHash: model.HashRules(string(encoded)),
 	}
+}
 
-	if mostFrequent == nil {
-		return nil, nil
+func toStatsResponse(entry model.StatsEntry) (*model.StatsResponse, error) {
+	rules := []model.Rule{
+		{Divisor: entry.Int1, Word: entry.Str1},
+		{Divisor: entry.Int2, Word: entry.Str2},
+	}
+	if entry.Rules != "" {
+		if err := json.Unmarshal([]byte(entry.Rules), &rules); err != nil {
+			return nil, fmt.Errorf("decode rules of stats entry %d: %w", entry.ID, err)
+		}
 	}
 
 	return &model.StatsResponse{
-		Request: model.FizzBuzzRequest{
-			Int1:  mostFrequent.Int1,
-			Int2:  mostFrequent.Int2,
-			Limit: mostFrequent.Limit,
-			Str1:  mostFrequent.Str1,
-			Str2:  mostFrequent.Str2,
-		},
-		HitCount: mostFrequent.HitCount,
+		ID:       entry.ID,
+		Request:  model.NewFizzBuzzRequest(rules, entry.Limit),
+		HitCount: entry.HitCount,
 	}, nil
 }
 
-func (r *statsRepository) recordInDatabase(ctx context.Context, request model.FizzBuzzRequest) error {
-	// PB sans transaction: si 2 requêtes simultanées avec les mêmes paramètres int1=3, int2=5, limit=15, str1="fizz", str2="buzz" :
-	// Problème : Les deux threads lisent la même ancienne valeur avant que l'autre ait fini sa mise à jour.
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// DÉBUT DE TRANSACTION - PostgreSQL pose un LOCK, donc requete B doit attendre la fin de A donc pas de pb de concurrence
-		entry := model.StatsEntry{
-			Int1:  request.Int1,
-			Int2:  request.Int2,
-			Limit: request.Limit,
-			Str1:  request.Str1,
-			Str2:  request.Str2,
-		}
+func toStatsExportEntry(entry model.StatsEntry) (model.StatsExportEntry, error) {
+	response, err := toStatsResponse(entry)
+	if err != nil {
+		return model.StatsExportEntry{}, err
+	}
+	return model.StatsExportEntry{
+		Request:   response.Request,
+		HitCount:  entry.HitCount,
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}, nil
+}
 
-		result := tx.Where(&entry).First(&entry)
-		if result.Error == gorm.ErrRecordNotFound {
-			entry.HitCount = 1
-			return tx.Create(&entry).Error
+// mergeImportEntries maps imported entries to stats rows, summing the hits of entries that share a
+// parameter set. Keys are returned in order of first appearance. Missing timestamps default to now.
+func mergeImportEntries(entries []model.StatsExportEntry, now time.Time) (map[string]*model.StatsEntry, []string, *model.StatsImportResult) {
+	merged := make(map[string]*model.StatsEntry, len(entries))
+	keys := make([]string, 0, len(entries))
+	result := &model.StatsImportResult{}
+
+	for _, imported := range entries {
+		entry := newStatsEntry(imported.Request)
+		entry.HitCount = imported.HitCount
+		entry.CreatedAt, entry.UpdatedAt = imported.CreatedAt.UTC(), imported.UpdatedAt.UTC()
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = now
 		}
-		if result.Error != nil {
-			return result.Error
+		if entry.UpdatedAt.IsZero() {
+			entry.UpdatedAt = entry.CreatedAt
 		}
+		result.Hits += entry.HitCount
+
+		key := entryKey(entry)
+		existing, exists := merged[key]
+		if !exists {
+			merged[key] = &entry
+			keys = append(keys, key)
+			continue
+		}
+		mergeStatsEntry(existing, entry)
+	}
 
-		entry.HitCount++
-		return tx.Save(&entry).Error
-	})
+	result.Entries = len(keys)
+	return merged, keys, result
 }
 
-func (r *statsRepository) getMostFrequentFromDatabase(ctx context.Context) (*model.StatsResponse, error) {
-	var entry model.StatsEntry
-	result := r.db.WithContext(ctx).Order("hit_count DESC").First(&entry)
-	if result.Error == gorm.ErrRecordNotFound {
-		return nil, nil
+// mergeStatsEntry adds the hits of other to entry, widening its first and last request times
+func mergeStatsEntry(entry *model.StatsEntry, other model.StatsEntry) {
+	entry.HitCount += other.HitCount
+	if other.CreatedAt.Before(entry.CreatedAt) {
+		entry.CreatedAt = other.CreatedAt
 	}
-	if result.Error != nil {
-		return nil, result.Error
+	if other.UpdatedAt.After(entry.UpdatedAt) {
+		entry.UpdatedAt = other.UpdatedAt
 	}
-
-	return &model.StatsResponse{
-		Request: model.FizzBuzzRequest{
-			Int1:  entry.Int1,
-			Int2:  entry.Int2,
-			Limit: entry.Limit,
-			Str1:  entry.Str1,
-			Str2:  entry.Str2,
-		},
-		HitCount: entry.HitCount,
-	}, nil
-}
-
-func (r *statsRepository) generateKey(request model.FizzBuzzRequest) string {
-	return fmt.Sprintf("%d_%d_%d_%s_%s", request.Int1, request.Int2, request.Limit, request.Str1, request.Str2)
 }
@@ -3,21 +3,32 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/database"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 )
 
+// newTestStatsRepository is NewStatsRepository, failing the test on error
+func newTestStatsRepository(t *testing.T, database *gorm.DB, cfg *config.Config) IStatsRepository {
+	t.Helper()
+	repo, err := NewStatsRepository(database, cfg)
+	require.NoError(t, err)
+	return repo
+}
+
 func TestStatsRepository_Memory_RecordRequest(t *testing.T) {
 	cfg := &config.Config{
 		Database: config.DatabaseConfig{
 			StatsStorage: "memory",
 		},
 	}
-	repo := NewStatsRepository(nil, cfg)
+	repo := newTestStatsRepository(t, nil, cfg)
 
 	request1 := model.FizzBuzzRequest{
 		Int1:  3,
@@ -49,7 +60,7 @@ func TestStatsRepository_Memory_GetMostFrequent_Multiple(t *testing.T) {
 			StatsStorage: "memory",
 		},
 	}
-	repo := NewStatsRepository(nil, cfg)
+	repo := newTestStatsRepository(t, nil, cfg)
 
 	request1 := model.FizzBuzzRequest{
 		Int1:  3,
@@ -93,7 +104,7 @@ func TestStatsRepository_Memory_GetMostFrequent_Empty(t *testing.T) {
 			StatsStorage: "memory",
 		},
 	}
-	repo := NewStatsRepository(nil, cfg)
+	repo := newTestStatsRepository(t, nil, cfg)
 
 	result, err := repo.GetMostFrequent(context.Background())
 	assert.NoError(t, err)
@@ -106,7 +117,7 @@ func TestStatsRepository_Memory_ThreadSafety(t *testing.T) {
 			StatsStorage: "memory",
 		},
 	}
-	repo := NewStatsRepository(nil, cfg)
+	repo := newTestStatsRepository(t, nil, cfg)
 
 	request := model.FizzBuzzRequest{
 		Int1:  3,
@@ -146,14 +157,7 @@ func TestStatsRepository_Memory_ThreadSafety(t *testing.T) {
 	assert.Equal(t, int64(numGoroutines*numRecords), result.HitCount)
 }
 
-func TestStatsRepository_Memory_GenerateKey(t *testing.T) {
-	cfg := &config.Config{
-		Database: config.DatabaseConfig{
-			StatsStorage: "memory",
-		},
-	}
-	repo := NewStatsRepository(nil, cfg).(*statsRepository)
-
+func TestStatsRepository_GenerateKey(t *testing.T) {
 	request1 := model.FizzBuzzRequest{
 		Int1:  3,
 		Int2:  5,
@@ -178,9 +182,9 @@ func TestStatsRepository_Memory_GenerateKey(t *testing.T) {
 		Str2:  "buzz",
 	}
 
-	key1 := repo.generateKey(request1)
-	key2 := repo.generateKey(request2)
-	key3 := repo.generateKey(request3)
+	key1 := generateKey(request1)
+	key2 := generateKey(request2)
+	key3 := generateKey(request3)
 
 	// Same requests should generate same keys
 	assert.Equal(t, key1, key2)
@@ -188,20 +192,31 @@ func TestStatsRepository_Memory_GenerateKey(t *testing.T) {
 	assert.NotEqual(t, key1, key3)
 }
 
-func TestStatsRepository_Database_Mode(t *testing.T) {
-	cfg := &config.Config{
-		Database: config.DatabaseConfig{
-			StatsStorage: "postgres",
-		},
+func TestNewStatsRepository_SelectsBackend(t *testing.T) {
+	tests := []struct {
+		storage  string
+		expected IStatsRepository
+	}{
+		{storage: "memory", expected: &memoryStatsRepository{}},
+		{storage: "postgres", expected: &gormStatsRepository{}},
+		{storage: "sqlite", expected: &gormStatsRepository{}},
 	}
 
-	// Create repository with nil database (we're not testing actual DB operations here)
-	// This tests the initialization and mode selection
-	repo := NewStatsRepository(nil, cfg).(*statsRepository)
+	for _, tt := range tests {
+		t.Run(tt.storage, func(t *testing.T) {
+			cfg := &config.Config{
+				Database: config.DatabaseConfig{
+					StatsStorage: tt.storage,
+				},
+			}
+
+			// Create repository with nil database (we're not testing actual DB operations here)
+			repo := newTestStatsRepository(t, nil, cfg)
+			defer repo.Close()
 
-	assert.False(t, repo.useMemory)
-	assert.Nil(t, repo.db) // We passed nil, so it should be nil
-	assert.NotNil(t, repo.memStats) // Should still be initialized
+			assert.IsType(t, tt.expected, repo)
+		})
+	}
 }
 
 func TestStatsRepository_Memory_MultipleDistinctRequests(t *testing.T) {
@@ -210,7 +225,7 @@ func TestStatsRepository_Memory_MultipleDistinctRequests(t *testing.T) {
 			StatsStorage: "memory",
 		},
 	}
-	repo := NewStatsRepository(nil, cfg)
+	repo := newTestStatsRepository(t, nil, cfg)
 
 	requests := []model.FizzBuzzRequest{
 		{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
@@ -235,4 +250,617 @@ func TestStatsRepository_Memory_MultipleDistinctRequests(t *testing.T) {
 	require.NotNil(t, result)
 	assert.Equal(t, requests[1], result.Request)
 	assert.Equal(t, int64(5), result.HitCount)
-}
\ No newline at end of file
+}
+
+func TestStatsRepository_Memory_Rules(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg)
+
+	threeRules := model.FizzBuzzRequest{
+		Limit: 100,
+		Rules: []model.Rule{
+			{Divisor: 3, Word: "Fizz"},
+			{Divisor: 5, Word: "Buzz"},
+			{Divisor: 7, Word: "Bazz"},
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		err := repo.RecordRequest(context.Background(), threeRules)
+		assert.NoError(t, err)
+	}
+
+	result, err := repo.GetMostFrequent(context.Background())
+	assert.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, threeRules, result.Request)
+	assert.Equal(t, int64(3), result.HitCount)
+}
+
+func TestStatsRepository_Memory_TwoRulesMatchLegacyShape(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg)
+
+	legacy := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	rules := model.FizzBuzzRequest{
+		Limit: 100,
+		Rules: []model.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}},
+	}
+
+	assert.NoError(t, repo.RecordRequest(context.Background(), legacy))
+	assert.NoError(t, repo.RecordRequest(context.Background(), rules))
+
+	// Both shapes count towards the same entry, reported in the legacy shape
+	result, err := repo.GetMostFrequent(context.Background())
+	assert.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, legacy, result.Request)
+	assert.Equal(t, int64(2), result.HitCount)
+}
+
+func TestStatsRepository_GenerateKey_Rules(t *testing.T) {
+	base := model.FizzBuzzRequest{
+		Limit: 100,
+		Rules: []model.Rule{{Divisor: 3, Word: "Fizz"}, {Divisor: 5, Word: "Buzz"}, {Divisor: 7, Word: "Bazz"}},
+	}
+	reordered := model.FizzBuzzRequest{
+		Limit: 100,
+		Rules: []model.Rule{{Divisor: 5, Word: "Buzz"}, {Divisor: 3, Word: "Fizz"}, {Divisor: 7, Word: "Bazz"}},
+	}
+
+	// Rule order changes the output, so it must change the key
+	assert.NotEqual(t, generateKey(base), generateKey(reordered))
+	assert.Equal(t, generateKey(base), generateKey(base))
+}
+
+func TestStatsRepository_Memory_GetTop(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg)
+
+	requests := []model.FizzBuzzRequest{
+		{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+		{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"},
+		{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"},
+		{Int1: 1, Int2: 10, Limit: 200, Str1: "a", Str2: "b"},
+	}
+
+	hitCounts := []int{2, 5, 2, 2}
+
+	for i, request := range requests {
+		for j := 0; j < hitCounts[i]; j++ {
+			err := repo.RecordRequest(context.Background(), request)
+			assert.NoError(t, err)
+		}
+	}
+
+	// Ties on hit count keep the order in which entries were first recorded
+	top, total, err := repo.GetTop(context.Background(), 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	require.Len(t, top, 3)
+	assert.Equal(t, requests[1], top[0].Request)
+	assert.Equal(t, int64(5), top[0].HitCount)
+	assert.Equal(t, requests[0], top[1].Request)
+	assert.Equal(t, requests[2], top[2].Request)
+
+	// Next page
+	top, total, err = repo.GetTop(context.Background(), 3, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	require.Len(t, top, 1)
+	assert.Equal(t, requests[3], top[0].Request)
+
+	// Past the end
+	top, total, err = repo.GetTop(context.Background(), 3, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Empty(t, top)
+}
+
+func TestStatsRepository_Memory_GetMostFrequent_TieBreak(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+
+	// Map iteration order is random: repeat to make sure the earliest entry always wins
+	for i := 0; i < 20; i++ {
+		repo := newTestStatsRepository(t, nil, cfg)
+
+		first := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+		second := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+		third := model.FizzBuzzRequest{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"}
+
+		for _, request := range []model.FizzBuzzRequest{first, second, third, third, second, first} {
+			assert.NoError(t, repo.RecordRequest(context.Background(), request))
+		}
+
+		result, err := repo.GetMostFrequent(context.Background())
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, first, result.Request)
+	}
+}
+
+func TestStatsRepository_Memory_GetMostFrequent_TieBreakMicroseconds(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+	base := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	first := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	second := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	// Both are created in the same microsecond: PostgreSQL stores them as simultaneous, so the lowest ID wins
+	repo.now = func() time.Time { return base.Add(400 * time.Nanosecond) }
+	require.NoError(t, repo.RecordRequest(context.Background(), first))
+	repo.now = func() time.Time { return base.Add(100 * time.Nanosecond) }
+	require.NoError(t, repo.RecordRequest(context.Background(), second))
+
+	result, err := repo.GetMostFrequent(context.Background())
+	assert.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, first, result.Request)
+
+	top, _, err := repo.GetTop(context.Background(), 2, 0)
+	assert.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, first, top[0].Request)
+	assert.Equal(t, second, top[1].Request)
+}
+
+func TestStatsRepository_Memory_GetMostFrequentBetween(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: time.Hour,
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+
+	base := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	// fizzBuzz is the most frequent overall, fooBar during the last hour
+	repo.now = func() time.Time { return base }
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.RecordRequest(context.Background(), fizzBuzz))
+	}
+	repo.now = func() time.Time { return base.Add(2 * time.Hour) }
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.RecordRequest(context.Background(), fooBar))
+	}
+	assert.NoError(t, repo.RecordRequest(context.Background(), fizzBuzz))
+
+	tests := []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		expected *model.StatsResponse
+	}{
+		{
+			name:     "last_bucket",
+			since:    base.Add(90 * time.Minute),
+			until:    base.Add(3 * time.Hour),
+			expected: &model.StatsResponse{ID: 2, Request: fooBar, HitCount: 3},
+		},
+		{
+			name:     "since_rounded_down_to_bucket",
+			since:    base,
+			until:    base.Add(3 * time.Hour),
+			expected: &model.StatsResponse{ID: 1, Request: fizzBuzz, HitCount: 6},
+		},
+		{
+			name:     "until_excluded",
+			since:    base,
+			until:    base.Add(90 * time.Minute),
+			expected: &model.StatsResponse{ID: 1, Request: fizzBuzz, HitCount: 5},
+		},
+		{
+			name:     "no_hits",
+			since:    base.Add(-48 * time.Hour),
+			until:    base.Add(-24 * time.Hour),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.GetMostFrequentBetween(context.Background(), tt.since, tt.until)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	// The cumulative count is unaffected
+	result, err := repo.GetMostFrequent(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{ID: 1, Request: fizzBuzz, HitCount: 6}, result)
+}
+
+func TestStatsRepository_Memory_BucketRetention(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: 24 * time.Hour,
+			BucketRetention:   7 * 24 * time.Hour,
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	key := generateKey(request)
+
+	for _, day := range []int{0, 1, 6, 9} {
+		repo.now = func() time.Time { return base.AddDate(0, 0, day) }
+		assert.NoError(t, repo.RecordRequest(context.Background(), request))
+	}
+
+	// Opening the day 9 bucket pruned days 0 and 1, older than 7 days
+	buckets := repo.buckets[key]
+	assert.Len(t, buckets, 2)
+	assert.Contains(t, buckets, time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	assert.Contains(t, buckets, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	result, err := repo.GetMostFrequent(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.HitCount)
+
+	// The periodic cleanup prunes entries no longer requested too
+	repo.now = func() time.Time { return base.AddDate(0, 0, 14) }
+	repo.pruneExpiredBuckets()
+	assert.Len(t, buckets, 1)
+	assert.Contains(t, buckets, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+}
+
+func TestStatsRepository_Memory_Eviction(t *testing.T) {
+	top := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	frequent := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	recent := model.FizzBuzzRequest{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"}
+	newcomer := model.FizzBuzzRequest{Int1: 1, Int2: 10, Limit: 200, Str1: "a", Str2: "b"}
+
+	tests := []struct {
+		name     string
+		policy   string
+		expected []model.FizzBuzzRequest
+	}{
+		// top is the least recently requested, but is never evicted: frequent goes instead
+		{name: "lru", policy: config.EvictionPolicyLRU, expected: []model.FizzBuzzRequest{top, recent, newcomer}},
+		{name: "lfu", policy: config.EvictionPolicyLFU, expected: []model.FizzBuzzRequest{top, frequent, newcomer}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Database: config.DatabaseConfig{
+					StatsStorage: "memory",
+				},
+				Stats: config.StatsConfig{
+					MemoryMaxEntries:     3,
+					MemoryEvictionPolicy: tt.policy,
+				},
+			}
+			repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+
+			base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+			record := func(minute int, request model.FizzBuzzRequest, times int) {
+				repo.now = func() time.Time { return base.Add(time.Duration(minute) * time.Minute) }
+				for i := 0; i < times; i++ {
+					assert.NoError(t, repo.RecordRequest(context.Background(), request))
+				}
+			}
+			record(0, top, 5)
+			record(1, frequent, 3)
+			record(2, recent, 1)
+			record(3, newcomer, 1)
+
+			top10, total, err := repo.GetTop(context.Background(), 10, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), total)
+			requests := make([]model.FizzBuzzRequest, 0, len(top10))
+			for _, item := range top10 {
+				requests = append(requests, item.Request)
+			}
+			assert.ElementsMatch(t, tt.expected, requests)
+
+			metrics, err := repo.GetStoreMetrics(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), metrics.Evictions)
+			assert.Equal(t, int64(3), metrics.Entries)
+			assert.Equal(t, 3, metrics.MaxEntries)
+		})
+	}
+}
+
+func TestStatsRepository_Memory_ImportEviction(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			MemoryMaxEntries:     2,
+			MemoryEvictionPolicy: config.EvictionPolicyLFU,
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg)
+
+	top := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	rare := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	imported := model.FizzBuzzRequest{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"}
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.RecordRequest(context.Background(), top))
+	}
+	assert.NoError(t, repo.RecordRequest(context.Background(), rare))
+
+	// Imports are bounded like recorded requests
+	_, err := repo.ImportEntries(context.Background(), []model.StatsExportEntry{{Request: imported, HitCount: 2}})
+	assert.NoError(t, err)
+
+	top10, total, err := repo.GetTop(context.Background(), 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []model.StatsResponse{{ID: 1, Request: top, HitCount: 3}, {ID: 3, Request: imported, HitCount: 2}}, top10)
+
+	metrics, err := repo.GetStoreMetrics(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.Evictions)
+}
+
+func TestStatsRepository_Memory_CleanupExpiredEntries(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			MemoryEntryTTL: time.Hour,
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg).(*memoryStatsRepository)
+
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	top := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	stale := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	fresh := model.FizzBuzzRequest{Int1: 4, Int2: 6, Limit: 75, Str1: "ping", Str2: "pong"}
+
+	repo.now = func() time.Time { return base }
+	assert.NoError(t, repo.RecordRequest(context.Background(), top))
+	assert.NoError(t, repo.RecordRequest(context.Background(), top))
+	assert.NoError(t, repo.RecordRequest(context.Background(), stale))
+	repo.now = func() time.Time { return base.Add(90 * time.Minute) }
+	assert.NoError(t, repo.RecordRequest(context.Background(), fresh))
+
+	repo.cleanupExpiredEntries()
+
+	// top expired too, but is kept so that GET /stats does not change
+	assert.Len(t, repo.entries, 2)
+	assert.Contains(t, repo.entries, generateKey(top))
+	assert.Contains(t, repo.entries, generateKey(fresh))
+	assert.NotContains(t, repo.buckets, generateKey(stale))
+
+	metrics, err := repo.GetStoreMetrics(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.Expirations)
+	assert.Equal(t, int64(0), metrics.Evictions)
+	assert.Equal(t, "1h0m0s", metrics.EntryTTL)
+}
+
+func TestStatsRepository_Memory_PeriodicCleanup(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "memory",
+		},
+		Stats: config.StatsConfig{
+			MemoryEntryTTL:        time.Millisecond,
+			MemoryCleanupInterval: 5 * time.Millisecond,
+		},
+	}
+	repo := newTestStatsRepository(t, nil, cfg)
+	defer repo.Close()
+
+	assert.NoError(t, repo.RecordRequest(context.Background(), model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}))
+	assert.NoError(t, repo.RecordRequest(context.Background(), model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}))
+
+	assert.Eventually(t, func() bool {
+		metrics, err := repo.GetStoreMetrics(context.Background())
+		return err == nil && metrics.Expirations == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// Close is idempotent
+	assert.NoError(t, repo.Close())
+	assert.NoError(t, repo.Close())
+}
+
+func TestStatsRepository_Batch_CoalescesHits(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "postgres",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: time.Hour,
+			FlushInterval:     time.Hour,
+			FlushMaxEntries:   3,
+		},
+	}
+	// No database: stop the flush goroutine so that hits stay buffered
+	repo := newTestStatsRepository(t, nil, cfg).(*gormStatsRepository)
+	close(repo.stop)
+	<-repo.flusherDone
+
+	base := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+
+	repo.now = func() time.Time { return base }
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, repo.RecordRequest(context.Background(), fizzBuzz))
+	}
+	assert.NoError(t, repo.RecordRequest(context.Background(), fooBar))
+	repo.now = func() time.Time { return base.Add(time.Hour) }
+	assert.NoError(t, repo.RecordRequest(context.Background(), fizzBuzz))
+
+	// One pending row per parameter set and bucket
+	require.Len(t, repo.batch, 3)
+	first := repo.batch[batchKey{entryKey: generateKey(fizzBuzz), bucketStart: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}]
+	require.NotNil(t, first)
+	assert.Equal(t, int64(4), first.hits)
+	assert.Equal(t, base, first.entry.CreatedAt)
+	assert.Equal(t, int64(6), repo.batchPendingHits)
+
+	// The third distinct row reached FlushMaxEntries and requested an early flush
+	assert.Len(t, repo.flushSignal, 1)
+}
+
+func TestStatsRepository_Batch_BoundedWhileFlushesFail(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "sqlite",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: time.Hour,
+			FlushInterval:     time.Hour,
+			FlushMaxEntries:   2,
+		},
+	}
+	// Not migrated: every flush fails
+	db, err := database.OpenSQLite(":memory:")
+	require.NoError(t, err)
+	repo := newTestStatsRepository(t, db, cfg).(*gormStatsRepository)
+	close(repo.stop)
+	<-repo.flusherDone
+
+	request := func(limit int) model.FizzBuzzRequest {
+		return model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: limit, Str1: "fizz", Str2: "buzz"}
+	}
+
+	// The batch is capped at 10 times FlushMaxEntries parameter sets
+	for limit := 1; limit <= 25; limit++ {
+		assert.NoError(t, repo.RecordRequest(context.Background(), request(limit)))
+	}
+	assert.Error(t, repo.flushBatch(context.Background()))
+	assert.NoError(t, repo.RecordRequest(context.Background(), request(1))) // Already buffered: counted
+	assert.NoError(t, repo.RecordRequest(context.Background(), request(26)))
+
+	assert.Len(t, repo.batch, 20)
+	assert.Equal(t, int64(21), repo.batchPendingHits)
+	assert.Equal(t, int64(6), repo.batchDroppedHits)
+	assert.Equal(t, int64(1), repo.flushErrors)
+}
+
+func TestStatsRepository_Batch_DeleteDiscardsPendingHits(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "sqlite",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: time.Hour,
+			FlushInterval:     time.Hour,
+			FlushMaxEntries:   10,
+		},
+	}
+	db, err := database.OpenSQLite(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.MigrateUp(db))
+	repo := newTestStatsRepository(t, db, cfg).(*gormStatsRepository)
+	close(repo.stop)
+	<-repo.flusherDone
+
+	ctx := context.Background()
+	fizzBuzz := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+	fooBar := model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"}
+	bazz := model.FizzBuzzRequest{Int1: 4, Int2: 9, Limit: 20, Str1: "baz", Str2: "bazz"}
+
+	// fizzBuzz is stored and buffered, fooBar only buffered, bazz only stored
+	require.NoError(t, repo.RecordRequest(ctx, fizzBuzz))
+	require.NoError(t, repo.RecordRequest(ctx, bazz))
+	require.NoError(t, repo.Flush(ctx))
+	require.NoError(t, repo.RecordRequest(ctx, fizzBuzz))
+	require.NoError(t, repo.RecordRequest(ctx, fooBar))
+
+	// The buffered hits of the deleted parameter set are discarded, not written
+	result, err := repo.DeleteRequest(ctx, fooBar)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 1, Hits: 1}, result)
+	assert.Len(t, repo.batch, 1)
+	assert.Equal(t, int64(1), repo.batchPendingHits)
+
+	result, err = repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.StatsDeleteResult{Entries: 2, Hits: 3}, result)
+	assert.Empty(t, repo.batch)
+	assert.Equal(t, int64(0), repo.batchPendingHits)
+
+	// A later flush brings nothing back
+	require.NoError(t, repo.Flush(ctx))
+	mostFrequent, err := repo.GetMostFrequent(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, mostFrequent)
+}
+
+func TestStatsRepository_Batch_DropsRefusedParameterSets(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			StatsStorage: "sqlite",
+		},
+		Stats: config.StatsConfig{
+			BucketGranularity: time.Hour,
+			FlushInterval:     time.Hour,
+			FlushMaxEntries:   10,
+		},
+	}
+	db, err := database.OpenSQLite(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.MigrateUp(db))
+	// Stands for a value PostgreSQL can never store, such as a NUL character
+	require.NoError(t, db.Exec(`CREATE TRIGGER refuse_poison BEFORE INSERT ON stats_entries WHEN NEW.str1 = 'poison'
+		BEGIN SELECT RAISE(ABORT, 'refused'); END`).Error)
+	repo := newTestStatsRepository(t, db, cfg).(*gormStatsRepository)
+	close(repo.stop)
+	<-repo.flusherDone
+
+	ctx := context.Background()
+	poison := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "poison", Str2: "buzz"}
+	for limit := 1; limit <= 5; limit++ {
+		require.NoError(t, repo.RecordRequest(ctx, model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: limit, Str1: "fizz", Str2: "buzz"}))
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.RecordRequest(ctx, poison))
+	}
+
+	// The other parameter sets are written, the refused one is dropped instead of blocking later flushes
+	require.NoError(t, repo.flushBatch(ctx))
+	_, total, err := repo.GetTop(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Empty(t, repo.batch)
+	assert.Equal(t, int64(0), repo.batchPendingHits)
+	assert.Equal(t, int64(3), repo.batchDroppedHits)
+	assert.Equal(t, int64(1), repo.flushErrors)
+
+	// Alone, it may be the database refusing every write: it is kept for the next flush
+	require.NoError(t, repo.RecordRequest(ctx, poison))
+	assert.Error(t, repo.flushBatch(ctx))
+	assert.Len(t, repo.batch, 1)
+	assert.Equal(t, int64(1), repo.batchPendingHits)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix versions the cursor format so it can evolve without misreading old cursors
+const cursorPrefix = "n:"
+
+// EncodeCursor returns the opaque cursor pointing at number n of a sequence.
+func EncodeCursor(n int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(n)))
+}
+
+// DecodeCursor returns the number a cursor produced by EncodeCursor points at.
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("invalid cursor: unknown format")
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid cursor: bad position %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, n := range []int{1, 42, 9000000} {
+		decoded, err := DecodeCursor(EncodeCursor(n))
+		assert.NoError(t, err)
+		assert.Equal(t, n, decoded)
+	}
+}
+
+func TestCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not_base64", cursor: "***"},
+		{name: "unknown_prefix", cursor: base64.RawURLEncoding.EncodeToString([]byte("x:12"))},
+		{name: "not_a_number", cursor: base64.RawURLEncoding.EncodeToString([]byte("n:abc"))},
+		{name: "zero_position", cursor: base64.RawURLEncoding.EncodeToString([]byte("n:0"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			assert.Error(t, err)
+		})
+	}
+}
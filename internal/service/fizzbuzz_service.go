@@ -3,76 +3,114 @@ package service
 import (
 	"context"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
-	"github.com/julietteengel/fizzbuzz-api/internal/repository"
-	"strconv"
+	"strings"
 )
 
 type IFizzBuzzService interface {
 	GenerateFizzBuzz(ctx context.Context, request model.FizzBuzzRequest) (*model.FizzBuzzResponse, error)
+	// StreamFizzBuzz computes the sequence (or its start..end range) lazily, handing each value to emit as soon as it is computed.
+	// It stops with ctx.Err() once ctx is cancelled, or with the first error returned by emit.
+	StreamFizzBuzz(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error
+	// RecordRequest counts a request in stats without generating anything, for responses served from a cache.
+	RecordRequest(ctx context.Context, request model.FizzBuzzRequest)
 }
 
+// cancellationCheckInterval is how many values are streamed between two context checks
+const cancellationCheckInterval = 1024
+
 type fizzBuzzService struct {
-	statsRepo repository.IStatsRepository
+	statsRecorder IStatsRecorder
 }
 
-func NewFizzBuzzService(statsRepo repository.IStatsRepository) IFizzBuzzService {
+func NewFizzBuzzService(statsRecorder IStatsRecorder) IFizzBuzzService {
 	return &fizzBuzzService{
-		statsRepo: statsRepo,
+		statsRecorder: statsRecorder,
 	}
 }
 
 func (s *fizzBuzzService) GenerateFizzBuzz(ctx context.Context, request model.FizzBuzzRequest) (*model.FizzBuzzResponse, error) {
-	result := make([]string, 0, request.Limit)
+	start, end := request.Window()
 
-	for i := 1; i <= request.Limit; i++ {
-		var value string
-		//
-		//Opérateur modulo (%) :
-		//- i % request.Int1 = reste de la division de i par request.Int1
-		//- Si le reste est 0, alors i est divisible par request.Int1
-		// "Est-ce que i divisé par Int1 donne un reste de 0 ?"
-		// Si oui → i est un multiple de Int1
-		// Si non → i n'est pas un multiple de Int1
-		isMultipleOfInt1 := i%request.Int1 == 0
-		isMultipleOfInt2 := i%request.Int2 == 0
-
-		switch {
-		case isMultipleOfInt1 && isMultipleOfInt2:
-			value = request.Str1 + request.Str2
-		case isMultipleOfInt1:
-			value = request.Str1
-		case isMultipleOfInt2:
-			value = request.Str2
-		default:
-			value = strconv.Itoa(i)
+	// Only the requested page is computed: values don't depend on each other, so there is no need to start from 1
+	pageStart := start
+	if request.Cursor != "" {
+		position, err := DecodeCursor(request.Cursor)
+		if err != nil {
+			return nil, err
 		}
+		pageStart = position
+	}
 
-		result = append(result, value)
+	pageEnd := end
+	if request.PageSize > 0 && pageStart+request.PageSize-1 < end {
+		pageEnd = pageStart + request.PageSize - 1
 	}
 
-	// Record request for statistics (async to not block response)
-	//- Goroutine pour éviter de bloquer la réponse HTTP avec l'enregistrement des stats
-	//- L'enregistrement des stats est un effet de bord non critique
-	//- Si la base de données est lente, on ne veut pas ralentir l'API
-	// PB: // Sans timeout - goroutine peut rester bloquée indéfiniment
-	go func() {
-		if err := s.statsRepo.RecordRequest(context.Background(), request); err != nil {
-			// Log error but don't fail the request
-		}
+	rules, formatNumber := outputRules(request)
+	result := make([]string, 0, max(pageEnd-pageStart+1, 0))
+
+	for i := pageStart; i <= pageEnd; i++ {
+		result = append(result, applyRules(rules, i, formatNumber))
+	}
 
-		// AMELIORATION POSSIBLE :
-		//  Avec timeout - goroutine se termine au bout de 5s max
-		//go func() {
-		//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		//	defer cancel() // Si DB répond jamais → cancel() se déclenche au bout de 5s  => Même si DB down, goroutine meurt après 5s (sinon fuite de ressources)
-		//	if err := s.statsRepo.RecordRequest(ctx, request); err != nil {
-		//		log.Errorf("Failed to record stats: %v", err)
-		//	}
-		//}()
-	}()
-
-	return &model.FizzBuzzResponse{
+	s.statsRecorder.Record(request)
+
+	response := &model.FizzBuzzResponse{
 		Result: result,
 		Count:  len(result),
-	}, nil
+		Start:  pageStart,
+		Total:  end - start + 1,
+	}
+	if pageEnd < end {
+		response.NextCursor = EncodeCursor(pageEnd + 1)
+	}
+	return response, nil
+}
+
+func (s *fizzBuzzService) StreamFizzBuzz(ctx context.Context, request model.FizzBuzzRequest, emit func(value string) error) error {
+	s.statsRecorder.Record(request)
+
+	rules, formatNumber := outputRules(request)
+	start, end := request.Window()
+	for i := start; i <= end; i++ {
+		// Client gone (or server shutting down): stop computing values nobody will read
+		if (i-start)%cancellationCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		if err := emit(applyRules(rules, i, formatNumber)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fizzBuzzService) RecordRequest(ctx context.Context, request model.FizzBuzzRequest) {
+	s.statsRecorder.Record(request)
+}
+
+// applyRules returns the concatenated words of every rule whose divisor divides i,
+// or i itself, written by formatNumber, when no rule matches.
+func applyRules(rules []model.Rule, i int, formatNumber func(int) string) string {
+	var value strings.Builder
+	for _, rule := range rules {
+		//
+		//Opérateur modulo (%) :
+		//- i % rule.Divisor = reste de la division de i par rule.Divisor
+		//- Si le reste est 0, alors i est divisible par rule.Divisor
+		// "Est-ce que i divisé par Divisor donne un reste de 0 ?"
+		// Si oui → i est un multiple de Divisor
+		// Si non → i n'est pas un multiple de Divisor
+		if i%rule.Divisor == 0 {
+			value.WriteString(rule.Word)
+		}
+	}
+
+	if value.Len() == 0 {
+		return formatNumber(i)
+	}
+	return value.String()
 }
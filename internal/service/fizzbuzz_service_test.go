@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -99,19 +98,69 @@ func TestFizzBuzzService_GenerateFizzBuzz(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "three_rules",
+			request: model.FizzBuzzRequest{
+				Limit: 21,
+				Rules: []model.Rule{
+					{Divisor: 3, Word: "Fizz"},
+					{Divisor: 5, Word: "Buzz"},
+					{Divisor: 7, Word: "Bazz"},
+				},
+			},
+			expected: &model.FizzBuzzResponse{
+				Result: []string{
+					"1", "2", "Fizz", "4", "Buzz", "Fizz", "Bazz", "8", "Fizz", "Buzz", "11", "Fizz", "13", "Bazz", "FizzBuzz",
+					"16", "17", "Fizz", "19", "Buzz", "FizzBazz",
+				},
+				Count: 21,
+			},
+			wantErr: false,
+		},
+		{
+			name: "single_rule",
+			request: model.FizzBuzzRequest{
+				Limit: 4,
+				Rules: []model.Rule{
+					{Divisor: 2, Word: "even"},
+				},
+			},
+			expected: &model.FizzBuzzResponse{
+				Result: []string{"1", "even", "3", "even"},
+				Count:  4,
+			},
+			wantErr: false,
+		},
+		{
+			name: "rules_order_is_preserved",
+			request: model.FizzBuzzRequest{
+				Limit: 15,
+				Rules: []model.Rule{
+					{Divisor: 5, Word: "buzz"},
+					{Divisor: 3, Word: "fizz"},
+				},
+			},
+			expected: &model.FizzBuzzResponse{
+				Result: []string{
+					"1", "2", "fizz", "4", "buzz", "fizz", "7", "8", "fizz", "buzz", "11", "fizz", "13", "14", "buzzfizz",
+				},
+				Count: 15,
+			},
+			wantErr: false,
+		},
 		// Note: Validation test cases removed as validation now happens only at controller level
 		// The service trusts that it receives valid data from the controller
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockStatsRepo := mocks.NewMockIStatsRepository(t)
+			mockRecorder := mocks.NewMockIStatsRecorder(t)
 			
 			if !tt.wantErr {
-				mockStatsRepo.EXPECT().RecordRequest(mock.Anything, tt.request).Return(nil).Once()
+				mockRecorder.EXPECT().Record(tt.request).Return().Once()
 			}
 
-			service := NewFizzBuzzService(mockStatsRepo)
+			service := NewFizzBuzzService(mockRecorder)
 			
 			result, err := service.GenerateFizzBuzz(context.Background(), tt.request)
 
@@ -124,36 +173,270 @@ func TestFizzBuzzService_GenerateFizzBuzz(t *testing.T) {
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.expected.Result, result.Result)
 				assert.Equal(t, tt.expected.Count, result.Count)
-				
-				// Wait a bit for the async goroutine to complete
-				time.Sleep(10 * time.Millisecond)
 			}
 		})
 	}
 }
 
-func TestFizzBuzzService_GenerateFizzBuzz_StatsError(t *testing.T) {
-	mockStatsRepo := mocks.NewMockIStatsRepository(t)
-	
+func TestFizzBuzzService_StreamFizzBuzz(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 15,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	var values []string
+	err := service.StreamFizzBuzz(context.Background(), request, func(value string) error {
+		values = append(values, value)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"1", "2", "fizz", "4", "buzz", "fizz", "7", "8", "fizz", "buzz", "11", "fizz", "13", "14", "fizzbuzz",
+	}, values)
+}
+
+func TestFizzBuzzService_StreamFizzBuzz_ContextCancelled(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 10000000,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	emitted := 0
+	err := service.StreamFizzBuzz(ctx, request, func(value string) error {
+		emitted++
+		if emitted == 10 {
+			cancel() // Simulates the client disconnecting mid-stream
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, emitted, request.Limit)
+}
+
+func TestFizzBuzzService_StreamFizzBuzz_EmitError(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 100,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	emitted := 0
+	err := service.StreamFizzBuzz(context.Background(), request, func(value string) error {
+		emitted++
+		if emitted == 3 {
+			return assert.AnError
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 3, emitted)
+}
+
+func TestFizzBuzzService_GenerateFizzBuzz_Window(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
 	request := model.FizzBuzzRequest{
 		Int1:  3,
 		Int2:  5,
-		Limit: 5,
+		Limit: 10000000,
 		Str1:  "fizz",
 		Str2:  "buzz",
+		Start: 9000000,
+		End:   9000005,
 	}
 
-	mockStatsRepo.EXPECT().RecordRequest(mock.Anything, request).Return(assert.AnError).Once()
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
 
-	service := NewFizzBuzzService(mockStatsRepo)
-	
 	result, err := service.GenerateFizzBuzz(context.Background(), request)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, []string{"1", "2", "fizz", "4", "buzz"}, result.Result)
-	assert.Equal(t, 5, result.Count)
-	
-	// Wait a bit for the async goroutine to complete
-	time.Sleep(10 * time.Millisecond)
-}
\ No newline at end of file
+	assert.Equal(t, []string{"fizzbuzz", "9000001", "9000002", "fizz", "9000004", "buzz"}, result.Result)
+	assert.Equal(t, 6, result.Count)
+	assert.Equal(t, 9000000, result.Start)
+	assert.Equal(t, 6, result.Total)
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestFizzBuzzService_GenerateFizzBuzz_Output(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  model.FizzBuzzRequest
+		expected []string
+	}{
+		{
+			name:     "numbers",
+			request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 1001, Str1: "Fizz", Str2: "Buzz", Start: 998, Locale: "ar"},
+			expected: []string{"٩٩٨", "Fizz", "Buzz", "١٬٠٠١"},
+		},
+		{
+			name:     "numbers_and_words",
+			request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 1001, Str1: "Fizz", Str2: "Buzz", Start: 998, Locale: "fr", LocalizeWords: true},
+			expected: []string{"998", "Pétille", "Bourdonne", "1\u202f001"},
+		},
+		{
+			name:     "decimal_with_locale",
+			request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 1001, Str1: "Fizz", Str2: "Buzz", Start: 998, Locale: "ar", NumberFormat: "Decimal"},
+			expected: []string{"٩٩٨", "Fizz", "Buzz", "١٬٠٠١"},
+		},
+		{
+			name:     "number_format",
+			request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 11, Str1: "fizz", Str2: "buzz", Start: 7, NumberFormat: "hex"},
+			expected: []string{"7", "8", "fizz", "buzz", "b"},
+		},
+		{
+			name: "words_without_preset",
+			request: model.FizzBuzzRequest{
+				Limit:         6,
+				Rules:         []model.Rule{{Divisor: 2, Word: "fizz"}, {Divisor: 3, Word: "bang"}},
+				Locale:        "es",
+				LocalizeWords: true,
+			},
+			expected: []string{"1", "burbuja", "bang", "burbuja", "5", "burbujabang"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecorder := mocks.NewMockIStatsRecorder(t)
+			mockRecorder.EXPECT().Record(tt.request).Return().Once()
+
+			result, err := NewFizzBuzzService(mockRecorder).GenerateFizzBuzz(context.Background(), tt.request)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Result)
+		})
+	}
+}
+
+func TestFizzBuzzService_GenerateFizzBuzz_Pages(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+	mockRecorder.EXPECT().Record(mock.Anything).Return().Times(3)
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	request := model.FizzBuzzRequest{
+		Int1:     3,
+		Int2:     5,
+		Limit:    15,
+		Str1:     "fizz",
+		Str2:     "buzz",
+		PageSize: 6,
+	}
+
+	// Follow next_cursor until the last page
+	var values []string
+	var pages int
+	for {
+		result, err := service.GenerateFizzBuzz(context.Background(), request)
+		assert.NoError(t, err)
+		assert.Equal(t, 15, result.Total)
+		assert.Equal(t, len(values)+1, result.Start)
+
+		values = append(values, result.Result...)
+		pages++
+		if result.NextCursor == "" {
+			break
+		}
+		request.Cursor = result.NextCursor
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{
+		"1", "2", "fizz", "4", "buzz", "fizz", "7", "8", "fizz", "buzz", "11", "fizz", "13", "14", "fizzbuzz",
+	}, values)
+}
+
+func TestFizzBuzzService_GenerateFizzBuzz_InvalidCursor(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	result, err := service.GenerateFizzBuzz(context.Background(), model.FizzBuzzRequest{
+		Int1:   3,
+		Int2:   5,
+		Limit:  15,
+		Str1:   "fizz",
+		Str2:   "buzz",
+		Cursor: "not-a-cursor",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestFizzBuzzService_StreamFizzBuzz_Window(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 100,
+		Str1:  "fizz",
+		Str2:  "buzz",
+		Start: 14,
+		End:   16,
+	}
+
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	var values []string
+	err := service.StreamFizzBuzz(context.Background(), request, func(value string) error {
+		values = append(values, value)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"14", "fizzbuzz", "16"}, values)
+}
+
+func TestFizzBuzzService_RecordRequest(t *testing.T) {
+	mockRecorder := mocks.NewMockIStatsRecorder(t)
+
+	request := model.FizzBuzzRequest{
+		Int1:  3,
+		Int2:  5,
+		Limit: 15,
+		Str1:  "fizz",
+		Str2:  "buzz",
+	}
+
+	mockRecorder.EXPECT().Record(request).Return().Once()
+
+	service := NewFizzBuzzService(mockRecorder)
+
+	service.RecordRequest(context.Background(), request)
+}
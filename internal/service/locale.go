@@ -0,0 +1,166 @@
+package service
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// DefaultLocale renders the numbers of requests asking for the locale of their Accept-Language when none matches
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// locale renders the numbers and words of a sequence for a language. Locales are read from
+// locales/<tag>.json: a locale is added by adding its file.
+type locale struct {
+	Digits         string            `json:"digits"`          // The digits 0 to 9
+	GroupSeparator string            `json:"group_separator"` // Written between groups of digits
+	GroupSizes     []int             `json:"group_sizes"`     // Sizes of the groups from the right, the last one repeating; [3] when empty
+	Words          map[string]string `json:"words"`           // Replacements of rule words with localize_words, by lower-case word
+
+	digits []rune
+}
+
+var locales, localeTags = mustLoadLocales(localeFiles)
+
+// loadLocales reads the locales/*.json files of files, named after their language tag
+func loadLocales(files fs.FS) (map[string]*locale, []string, error) {
+	paths, err := fs.Glob(files, "locales/*.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded := make(map[string]*locale, len(paths))
+	tags := make([]string, 0, len(paths))
+	for _, file := range paths {
+		content, err := fs.ReadFile(files, file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var loc locale
+		if err := json.Unmarshal(content, &loc); err != nil {
+			return nil, nil, fmt.Errorf("invalid locale file %s: %w", file, err)
+		}
+
+		loc.digits = []rune(loc.Digits)
+		if len(loc.digits) != 10 {
+			return nil, nil, fmt.Errorf("invalid locale file %s: digits must list the 10 digits, got %q", file, loc.Digits)
+		}
+		if len(loc.GroupSizes) == 0 {
+			loc.GroupSizes = []int{3}
+		}
+		for _, size := range loc.GroupSizes {
+			if size <= 0 {
+				return nil, nil, fmt.Errorf("invalid locale file %s: group sizes must be positive", file)
+			}
+		}
+
+		tag := strings.TrimSuffix(path.Base(file), ".json")
+		loaded[tag] = &loc
+		tags = append(tags, tag)
+	}
+
+	if _, ok := loaded[DefaultLocale]; !ok {
+		return nil, nil, fmt.Errorf("missing locale file for the default locale %s", DefaultLocale)
+	}
+
+	sort.Strings(tags)
+	return loaded, tags, nil
+}
+
+// mustLoadLocales loads the embedded locales: the files are part of the binary, so an error is a build error
+func mustLoadLocales(files fs.FS) (map[string]*locale, []string) {
+	loaded, tags, err := loadLocales(files)
+	if err != nil {
+		panic(err)
+	}
+	return loaded, tags
+}
+
+// Locales returns the tags of the locales accepted by FizzBuzzRequest.Locale, sorted
+func Locales() []string {
+	return append([]string(nil), localeTags...)
+}
+
+// formatNumber writes i with the digits and digit grouping of the locale
+func (l *locale) formatNumber(i int) string {
+	decimal := strconv.Itoa(i)
+	sign := ""
+	if i < 0 {
+		sign, decimal = "-", decimal[1:]
+	}
+
+	// Separators go at the group boundaries, counted from the last digit
+	boundaries := make(map[int]bool)
+	for position, group := len(decimal), 0; ; group++ {
+		position -= l.GroupSizes[min(group, len(l.GroupSizes)-1)]
+		if position <= 0 {
+			break
+		}
+		boundaries[position] = true
+	}
+
+	var formatted strings.Builder
+	formatted.WriteString(sign)
+	for position, digit := range decimal {
+		if boundaries[position] {
+			formatted.WriteString(l.GroupSeparator)
+		}
+		formatted.WriteRune(l.digits[digit-'0'])
+	}
+	return formatted.String()
+}
+
+// localizeWord returns the preset of the locale for word, with the case of word (fizz, Fizz, FIZZ),
+// or word itself when the locale has no preset for it
+func (l *locale) localizeWord(word string) string {
+	preset, ok := l.Words[strings.ToLower(word)]
+	if !ok {
+		return word
+	}
+
+	switch first, size := utf8.DecodeRuneInString(word); {
+	case size < len(word) && word == strings.ToUpper(word):
+		return strings.ToUpper(preset)
+	case unicode.IsUpper(first):
+		presetFirst, presetSize := utf8.DecodeRuneInString(preset)
+		return string(unicode.ToUpper(presetFirst)) + preset[presetSize:]
+	default:
+		return preset
+	}
+}
+
+// outputRules returns the rules and number formatting of request: the digits of its locale, which
+// requests only combine with decimal numbers, or else its number format, and the words of its locale
+// with localize_words
+func outputRules(request model.FizzBuzzRequest) ([]model.Rule, func(int) string) {
+	rules := request.EffectiveRules()
+	formatNumber := numberFormatter(request.NumberFormat)
+
+	loc, ok := locales[request.Locale]
+	if !ok {
+		return rules, formatNumber
+	}
+	formatNumber = loc.formatNumber
+
+	if request.LocalizeWords {
+		localized := make([]model.Rule, 0, len(rules))
+		for _, rule := range rules {
+			localized = append(localized, model.Rule{Divisor: rule.Divisor, Word: loc.localizeWord(rule.Word)})
+		}
+		rules = localized
+	}
+	return rules, formatNumber
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale_FormatNumber(t *testing.T) {
+	tests := []struct {
+		locale   string
+		number   int
+		expected string
+	}{
+		{locale: "en", number: 7, expected: "7"},
+		{locale: "en", number: 999, expected: "999"},
+		{locale: "en", number: 1000, expected: "1,000"},
+		{locale: "en", number: 1234567, expected: "1,234,567"},
+		{locale: "fr", number: 10000, expected: "10\u202f000"},
+		{locale: "de", number: 123456, expected: "123.456"},
+		{locale: "ar", number: 1234, expected: "١٬٢٣٤"},
+		{locale: "hi", number: 12345678, expected: "१,२३,४५,६७८"},
+		{locale: "hi", number: 100, expected: "१००"},
+		{locale: "en", number: -1234, expected: "-1,234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, locales[tt.locale].formatNumber(tt.number))
+		})
+	}
+}
+
+func TestLocale_LocalizeWord(t *testing.T) {
+	fr := locales["fr"]
+
+	assert.Equal(t, "pétille", fr.localizeWord("fizz"))
+	assert.Equal(t, "Pétille", fr.localizeWord("Fizz"))
+	assert.Equal(t, "BOURDONNE", fr.localizeWord("BUZZ"))
+	assert.Equal(t, "bang", fr.localizeWord("bang"))
+	assert.Equal(t, "fizz", locales["en"].localizeWord("fizz"))
+}
+
+func TestLoadLocales(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		loaded, tags, err := loadLocales(fstest.MapFS{
+			"locales/en.json":    {Data: []byte(`{"digits": "0123456789", "group_separator": ","}`)},
+			"locales/th-TH.json": {Data: []byte(`{"digits": "๐๑๒๓๔๕๖๗๘๙", "group_separator": ","}`)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"en", "th-TH"}, tags)
+		assert.Equal(t, []int{3}, loaded["th-TH"].GroupSizes)
+		assert.Equal(t, "๑,๒๓๔", loaded["th-TH"].formatNumber(1234))
+	})
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		errText string
+	}{
+		{
+			name:    "invalid_json",
+			files:   fstest.MapFS{"locales/en.json": {Data: []byte(`{"digits": 10}`)}},
+			errText: "locales/en.json",
+		},
+		{
+			name:    "missing_digits",
+			files:   fstest.MapFS{"locales/en.json": {Data: []byte(`{"digits": "012345678"}`)}},
+			errText: "10 digits",
+		},
+		{
+			name:    "invalid_group_size",
+			files:   fstest.MapFS{"locales/en.json": {Data: []byte(`{"digits": "0123456789", "group_sizes": [3, 0]}`)}},
+			errText: "group sizes",
+		},
+		{
+			name:    "missing_default_locale",
+			files:   fstest.MapFS{"locales/fr.json": {Data: []byte(`{"digits": "0123456789"}`)}},
+			errText: "default locale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := loadLocales(tt.files)
+			assert.ErrorContains(t, err, tt.errText)
+		})
+	}
+}
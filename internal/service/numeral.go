@@ -0,0 +1,83 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Named values of FizzBuzzRequest.NumberFormat, which also accepts base2 to base36
+const (
+	NumberFormatDecimal = "decimal"
+	NumberFormatBinary  = "binary"
+	NumberFormatOctal   = "octal"
+	NumberFormatHex     = "hex"
+	NumberFormatRoman   = "roman"
+
+	// MaxRomanNumeral is the largest number written with standard Roman numerals
+	MaxRomanNumeral = 3999
+)
+
+// romanNumerals lists the symbols of Roman numerals by decreasing value, subtractive pairs included
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// NumberBase returns the base of a number format: 10 for the empty (default) format, 0 for Roman numerals.
+// It returns false when format is unknown. Formats are case-insensitive.
+func NumberBase(format string) (int, bool) {
+	format = strings.ToLower(format)
+	switch format {
+	case "", NumberFormatDecimal:
+		return 10, true
+	case NumberFormatBinary:
+		return 2, true
+	case NumberFormatOctal:
+		return 8, true
+	case NumberFormatHex:
+		return 16, true
+	case NumberFormatRoman:
+		return 0, true
+	}
+
+	digits, found := strings.CutPrefix(format, "base")
+	base, err := strconv.Atoi(digits)
+	if !found || err != nil || base < 2 || base > 36 {
+		return 0, false
+	}
+	return base, true
+}
+
+// numberFormatter returns the function writing numbers in format, decimal when format is unknown
+func numberFormatter(format string) func(int) string {
+	base, ok := NumberBase(format)
+	switch {
+	case !ok || base == 10:
+		return strconv.Itoa
+	case base == 0:
+		return formatRoman
+	default:
+		return func(i int) string {
+			return strconv.FormatInt(int64(i), base)
+		}
+	}
+}
+
+// formatRoman writes i in Roman numerals, or in decimal outside 1..MaxRomanNumeral where they don't exist
+func formatRoman(i int) string {
+	if i < 1 || i > MaxRomanNumeral {
+		return strconv.Itoa(i)
+	}
+
+	var numeral strings.Builder
+	for _, symbol := range romanNumerals {
+		for ; i >= symbol.value; i -= symbol.value {
+			numeral.WriteString(symbol.numeral)
+		}
+	}
+	return numeral.String()
+}
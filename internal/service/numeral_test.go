@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberBase(t *testing.T) {
+	tests := []struct {
+		format       string
+		expectedBase int
+		expectedOK   bool
+	}{
+		{format: "", expectedBase: 10, expectedOK: true},
+		{format: "decimal", expectedBase: 10, expectedOK: true},
+		{format: "binary", expectedBase: 2, expectedOK: true},
+		{format: "octal", expectedBase: 8, expectedOK: true},
+		{format: "HEX", expectedBase: 16, expectedOK: true},
+		{format: "roman", expectedBase: 0, expectedOK: true},
+		{format: "base2", expectedBase: 2, expectedOK: true},
+		{format: "base36", expectedBase: 36, expectedOK: true},
+		{format: "base1"},
+		{format: "base37"},
+		{format: "base"},
+		{format: "base-8"},
+		{format: "klingon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			base, ok := NumberBase(tt.format)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedBase, base)
+		})
+	}
+}
+
+func TestNumberFormatter(t *testing.T) {
+	tests := []struct {
+		format   string
+		number   int
+		expected string
+	}{
+		{format: "", number: 42, expected: "42"},
+		{format: "binary", number: 10, expected: "1010"},
+		{format: "octal", number: 64, expected: "100"},
+		{format: "hex", number: 255, expected: "ff"},
+		{format: "base36", number: 1295, expected: "zz"},
+		{format: "base7", number: 49, expected: "100"},
+		{format: "roman", number: 1, expected: "I"},
+		{format: "roman", number: 4, expected: "IV"},
+		{format: "roman", number: 14, expected: "XIV"},
+		{format: "roman", number: 1994, expected: "MCMXCIV"},
+		{format: "roman", number: 3999, expected: "MMMCMXCIX"},
+		{format: "roman", number: 4000, expected: "4000"},
+		{format: "unknown", number: 12, expected: "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"_"+tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, numberFormatter(tt.format)(tt.number))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+	"github.com/julietteengel/fizzbuzz-api/internal/repository"
+)
+
+// IStatsRecorder records requests in stats off the request path.
+type IStatsRecorder interface {
+	// Record queues the request for recording and returns without waiting for the write.
+	// When the queue is full it drops the record or waits for room, depending on the overflow policy.
+	Record(request model.FizzBuzzRequest)
+	// Close stops accepting records and waits until queued ones are written, or until ctx is done.
+	Close(ctx context.Context) error
+}
+
+// Used when the matching config value is not set
+const (
+	defaultRecorderQueueSize    = 1000
+	defaultRecorderWorkers      = 1
+	defaultRecorderWriteTimeout = 5 * time.Second
+)
+
+// dropLogInterval is the minimum time between two warnings about dropped records, which are counted in between
+const dropLogInterval = 10 * time.Second
+
+// statsRecorder writes queued requests with a fixed pool of workers:
+//   - L'enregistrement des stats est un effet de bord non critique, la réponse HTTP ne l'attend pas
+//   - La file bornée et le nombre fixe de workers limitent la mémoire et les connexions utilisées si la base est lente
+//   - Chaque écriture a un timeout : même si la base ne répond jamais, les workers ne restent pas bloqués
+type statsRecorder struct {
+	statsRepo     repository.IStatsRepository
+	queue         chan model.FizzBuzzRequest
+	writeTimeout  time.Duration
+	blockWhenFull bool
+
+	closeMutex sync.RWMutex // Guards closed and the registration of senders, never held during a send
+	closed     bool
+	closing    chan struct{}  // Closed by Close: blocked senders give up instead of holding up shutdown
+	senders    sync.WaitGroup // Record calls that may still send to the queue, which is closed once they are done
+	workers    sync.WaitGroup
+
+	dropped         atomic.Int64
+	droppedSinceLog atomic.Int64 // Dropped since the last warning, see dropLogInterval
+	lastDropLog     atomic.Int64 // Unix nanoseconds of the last warning
+	failed          atomic.Int64
+}
+
+func NewStatsRecorder(statsRepo repository.IStatsRepository, cfg *config.Config) IStatsRecorder {
+	queueSize := cfg.Stats.RecorderQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultRecorderQueueSize
+	}
+	workers := cfg.Stats.RecorderWorkers
+	if workers <= 0 {
+		workers = defaultRecorderWorkers
+	}
+	writeTimeout := cfg.Stats.RecorderWriteTimeout
+	if writeTimeout <= 0 {
+		writeTimeout = defaultRecorderWriteTimeout
+	}
+
+	recorder := &statsRecorder{
+		statsRepo:     statsRepo,
+		queue:         make(chan model.FizzBuzzRequest, queueSize),
+		writeTimeout:  writeTimeout,
+		blockWhenFull: cfg.Stats.RecorderOverflowPolicy == config.OverflowPolicyBlock,
+		closing:       make(chan struct{}),
+	}
+
+	recorder.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go recorder.work()
+	}
+
+	return recorder
+}
+
+func (r *statsRecorder) Record(request model.FizzBuzzRequest) {
+	r.closeMutex.RLock()
+	if r.closed {
+		r.closeMutex.RUnlock()
+		r.drop("recorder closed")
+		return
+	}
+	r.senders.Add(1)
+	r.closeMutex.RUnlock()
+	defer r.senders.Done()
+
+	if r.blockWhenFull {
+		select {
+		case r.queue <- request:
+		case <-r.closing:
+			r.drop("recorder closed")
+		}
+		return
+	}
+
+	select {
+	case r.queue <- request:
+	default:
+		r.drop("queue full")
+	}
+}
+
+func (r *statsRecorder) Close(ctx context.Context) error {
+	r.closeMutex.Lock()
+	closing := !r.closed
+	r.closed = true
+	r.closeMutex.Unlock()
+
+	if closing {
+		close(r.closing)
+		// Senders registered before closed was set return at once now, so this never waits on the database
+		r.senders.Wait()
+		close(r.queue) // Workers exit once the queue is drained
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		r.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		if dropped, failed := r.dropped.Load(), r.failed.Load(); dropped > 0 || failed > 0 {
+			log.Warnf("Stats recorder stopped: %d records dropped, %d writes failed", dropped, failed)
+		}
+		return nil
+	case <-ctx.Done():
+		log.Errorf("Stats recorder stopped with %d records still queued: %v", len(r.queue), ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func (r *statsRecorder) work() {
+	defer r.workers.Done()
+
+	for request := range r.queue {
+		r.write(request)
+	}
+}
+
+func (r *statsRecorder) write(request model.FizzBuzzRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.writeTimeout)
+	defer cancel() // Si la base ne répond jamais, l'écriture est annulée au bout de writeTimeout
+
+	if err := r.statsRepo.RecordRequest(ctx, request); err != nil {
+		r.failed.Add(1)
+		log.Errorf("Failed to record stats: %v", err)
+	}
+}
+
+// drop counts a record that is not written. Warnings are rate-limited: under overload, one per dropped record would flood the logs.
+func (r *statsRecorder) drop(reason string) {
+	r.dropped.Add(1)
+	r.droppedSinceLog.Add(1)
+
+	now, last := time.Now().UnixNano(), r.lastDropLog.Load()
+	if now-last < int64(dropLogInterval) || !r.lastDropLog.CompareAndSwap(last, now) {
+		return
+	}
+	log.Warnf("%d stats records dropped since the last warning (%s)", r.droppedSinceLog.Swap(0), reason)
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
+	"github.com/julietteengel/fizzbuzz-api/internal/mocks"
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func newRecorderConfig(queueSize, workers int, overflowPolicy string) *config.Config {
+	return &config.Config{
+		Stats: config.StatsConfig{
+			RecorderQueueSize:      queueSize,
+			RecorderWorkers:        workers,
+			RecorderWriteTimeout:   time.Second,
+			RecorderOverflowPolicy: overflowPolicy,
+		},
+	}
+}
+
+func TestStatsRecorder_CloseDrainsQueue(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	// Slow writes: records are still queued when Close is called
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			time.Sleep(time.Millisecond)
+			return nil
+		}).Times(50)
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(100, 2, config.OverflowPolicyDrop))
+	for i := 0; i < 50; i++ {
+		recorder.Record(request)
+	}
+
+	err := recorder.Close(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), recorder.(*statsRecorder).dropped.Load())
+}
+
+func TestStatsRecorder_RepositoryError(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 5, Str1: "fizz", Str2: "buzz"}
+
+	// A failed write is logged and counted, and does not stop the worker
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).Return(assert.AnError).Once()
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).Return(nil).Once()
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(10, 1, config.OverflowPolicyDrop))
+	recorder.Record(request)
+	recorder.Record(request)
+
+	require.NoError(t, recorder.Close(context.Background()))
+	assert.Equal(t, int64(1), recorder.(*statsRecorder).failed.Load())
+}
+
+func TestStatsRecorder_WriteTimeout(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(20*time.Millisecond), deadline, 10*time.Millisecond)
+
+			<-ctx.Done() // Database never answers
+			return ctx.Err()
+		}).Once()
+
+	cfg := newRecorderConfig(10, 1, config.OverflowPolicyDrop)
+	cfg.Stats.RecorderWriteTimeout = 20 * time.Millisecond
+	recorder := NewStatsRecorder(mockRepo, cfg)
+	recorder.Record(request)
+
+	require.NoError(t, recorder.Close(context.Background()))
+	assert.Equal(t, int64(1), recorder.(*statsRecorder).failed.Load())
+}
+
+func TestStatsRecorder_DropWhenFull(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		}).Times(2)
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(1, 1, config.OverflowPolicyDrop))
+
+	recorder.Record(request) // Taken by the worker
+	<-started
+	recorder.Record(request) // Queued
+	recorder.Record(request) // Dropped: the queue is full
+
+	assert.Equal(t, int64(1), recorder.(*statsRecorder).dropped.Load())
+
+	close(release)
+	require.NoError(t, recorder.Close(context.Background()))
+}
+
+func TestStatsRecorder_BlockWhenFull(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		}).Times(3)
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(1, 1, config.OverflowPolicyBlock))
+
+	recorder.Record(request) // Taken by the worker
+	<-started
+	recorder.Record(request) // Queued
+
+	recorded := make(chan struct{})
+	go func() {
+		recorder.Record(request) // Waits for room in the queue
+		close(recorded)
+	}()
+
+	select {
+	case <-recorded:
+		t.Fatal("Record returned while the queue was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-recorded
+	require.NoError(t, recorder.Close(context.Background()))
+	assert.Equal(t, int64(0), recorder.(*statsRecorder).dropped.Load())
+}
+
+func TestStatsRecorder_CloseTimeout(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	release := make(chan struct{})
+	defer close(release)
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			<-release
+			return nil
+		}).Maybe()
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(10, 1, config.OverflowPolicyDrop))
+	recorder.Record(request)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := recorder.Close(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStatsRecorder_CloseWhileBlocked(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"}
+
+	// The database never answers: the worker is stuck and the queue stays full
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	mockRepo.EXPECT().RecordRequest(mock.Anything, request).
+		RunAndReturn(func(ctx context.Context, request model.FizzBuzzRequest) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		}).Maybe()
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(1, 1, config.OverflowPolicyBlock))
+	recorder.Record(request) // Taken by the worker
+	<-started
+	recorder.Record(request) // Queued
+
+	recorded := make(chan struct{})
+	go func() {
+		recorder.Record(request) // Waits for room in the queue
+		close(recorded)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// Close gives up at its deadline instead of waiting for the blocked sender, which drops its record
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, recorder.Close(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Fatal("Record still blocked after Close")
+	}
+	assert.Equal(t, int64(1), recorder.(*statsRecorder).dropped.Load())
+}
+
+func TestStatsRecorder_RecordAfterClose(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	recorder := NewStatsRecorder(mockRepo, newRecorderConfig(10, 1, config.OverflowPolicyBlock))
+	require.NoError(t, recorder.Close(context.Background()))
+
+	// Requests still in flight during shutdown must not panic on the closed queue
+	recorder.Record(model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 15, Str1: "fizz", Str2: "buzz"})
+
+	assert.Equal(t, int64(1), recorder.(*statsRecorder).dropped.Load())
+	assert.NoError(t, recorder.Close(context.Background()))
+}
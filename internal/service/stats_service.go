@@ -2,25 +2,115 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 	"github.com/julietteengel/fizzbuzz-api/internal/repository"
 )
 
 type IStatsService interface {
 	GetMostFrequent(ctx context.Context) (*model.StatsResponse, error)
+	GetTop(ctx context.Context, n, offset int) (*model.StatsTopResponse, error)
+	// GetMostFrequentBetween returns the most frequent request counting only hits between since and until.
+	GetMostFrequentBetween(ctx context.Context, since, until time.Time) (*model.StatsResponse, error)
+	GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error)
+	// ExportStats returns every stats entry, most hits first.
+	ExportStats(ctx context.Context) (*model.StatsExport, error)
+	// ImportStats adds entries to the stats, summing hit counts with the entries already stored.
+	// Invalid entries are reported as a *StatsImportError, and then nothing is imported.
+	ImportStats(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error)
+	// ResetStats deletes every stats entry.
+	ResetStats(ctx context.Context) (*model.StatsDeleteResult, error)
+	// DeleteStatsEntry deletes the entry with id. The result counts no entry when there is none.
+	DeleteStatsEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error)
+	// DeleteStatsRequest deletes the entry of the parameter set of request, given in legacy or rules form.
+	DeleteStatsRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error)
 }
 
 type statsService struct {
 	statsRepo repository.IStatsRepository
+
+	// Bounds of imported parameter sets, those of the requests the API accepts
+	minDivisor    int
+	maxDivisor    int // 0 for no maximum
+	minWordLength int
+	maxWordLength int
 }
 
-func NewStatsService(statsRepo repository.IStatsRepository) IStatsService {
+func NewStatsService(statsRepo repository.IStatsRepository, cfg *config.Config) IStatsService {
 	return &statsService{
 		statsRepo: statsRepo,
+
+		minDivisor:    cfg.App.MinDivisor,
+		maxDivisor:    cfg.App.MaxDivisor,
+		minWordLength: cfg.App.MinStringLength,
+		maxWordLength: cfg.App.MaxStringLength,
 	}
 }
 
 func (s *statsService) GetMostFrequent(ctx context.Context) (*model.StatsResponse, error) {
 	return s.statsRepo.GetMostFrequent(ctx)
-}
\ No newline at end of file
+}
+
+func (s *statsService) GetTop(ctx context.Context, n, offset int) (*model.StatsTopResponse, error) {
+	items, total, err := s.statsRepo.GetTop(ctx, n, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StatsTopResponse{
+		Items:  items,
+		N:      n,
+		Offset: offset,
+		Total:  total,
+	}, nil
+}
+
+func (s *statsService) GetStoreMetrics(ctx context.Context) (*model.StatsStoreMetrics, error) {
+	return s.statsRepo.GetStoreMetrics(ctx)
+}
+
+func (s *statsService) GetMostFrequentBetween(ctx context.Context, since, until time.Time) (*model.StatsResponse, error) {
+	stats, err := s.statsRepo.GetMostFrequentBetween(ctx, since, until)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+
+	stats.Since, stats.Until = &since, &until
+	return stats, nil
+}
+
+func (s *statsService) ExportStats(ctx context.Context) (*model.StatsExport, error) {
+	entries, err := s.statsRepo.ExportEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StatsExport{
+		ExportedAt: time.Now().UTC(),
+		Entries:    entries,
+	}, nil
+}
+
+func (s *statsService) ImportStats(ctx context.Context, entries []model.StatsExportEntry) (*model.StatsImportResult, error) {
+	for i, entry := range entries {
+		if reason := s.validateStatsImportEntry(entry); reason != "" {
+			return nil, &StatsImportError{Entry: i + 1, Reason: reason}
+		}
+	}
+
+	return s.statsRepo.ImportEntries(ctx, entries)
+}
+
+func (s *statsService) ResetStats(ctx context.Context) (*model.StatsDeleteResult, error) {
+	return s.statsRepo.DeleteAll(ctx)
+}
+
+func (s *statsService) DeleteStatsEntry(ctx context.Context, id uint) (*model.StatsDeleteResult, error) {
+	return s.statsRepo.DeleteEntry(ctx, id)
+}
+
+func (s *statsService) DeleteStatsRequest(ctx context.Context, request model.FizzBuzzRequest) (*model.StatsDeleteResult, error) {
+	return s.statsRepo.DeleteRequest(ctx, request)
+}
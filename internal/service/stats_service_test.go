@@ -3,14 +3,27 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/julietteengel/fizzbuzz-api/internal/config"
 	"github.com/julietteengel/fizzbuzz-api/internal/mocks"
 	"github.com/julietteengel/fizzbuzz-api/internal/model"
 )
 
+func newStatsServiceConfig() *config.Config {
+	return &config.Config{
+		App: config.AppConfig{
+			MinStringLength: 1,
+			MaxStringLength: 10,
+			MinDivisor:      1,
+			MaxDivisor:      1000,
+		},
+	}
+}
+
 func TestStatsService_GetMostFrequent(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -68,7 +81,7 @@ func TestStatsService_GetMostFrequent(t *testing.T) {
 			mockRepo := mocks.NewMockIStatsRepository(t)
 			tt.mockSetup(mockRepo)
 
-			service := NewStatsService(mockRepo)
+			service := NewStatsService(mockRepo, newStatsServiceConfig())
 
 			result, err := service.GetMostFrequent(context.Background())
 
@@ -107,11 +120,179 @@ func TestStatsService_GetMostFrequent_ContextPassing(t *testing.T) {
 
 	mockRepo.EXPECT().GetMostFrequent(ctx).Return(expectedResponse, nil).Once()
 
-	service := NewStatsService(mockRepo)
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
 	
 	result, err := service.GetMostFrequent(ctx)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, expectedResponse, result)
-}
\ No newline at end of file
+}
+
+func TestStatsService_GetTop(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	items := []model.StatsResponse{
+		{
+			Request:  model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+			HitCount: 42,
+		},
+		{
+			Request:  model.FizzBuzzRequest{Int1: 2, Int2: 7, Limit: 50, Str1: "foo", Str2: "bar"},
+			HitCount: 15,
+		},
+	}
+
+	mockRepo.EXPECT().GetTop(mock.Anything, 2, 4).Return(items, int64(12), nil).Once()
+
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	result, err := service.GetTop(context.Background(), 2, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.StatsTopResponse{
+		Items:  items,
+		N:      2,
+		Offset: 4,
+		Total:  12,
+	}, result)
+}
+
+func TestStatsService_GetTop_RepositoryError(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	mockRepo.EXPECT().GetTop(mock.Anything, 10, 0).Return(nil, int64(0), assert.AnError).Once()
+
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	result, err := service.GetTop(context.Background(), 10, 0)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestStatsService_GetMostFrequentBetween(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	since := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour)
+	request := model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}
+
+	mockRepo.EXPECT().GetMostFrequentBetween(mock.Anything, since, until).
+		Return(&model.StatsResponse{Request: request, HitCount: 7}, nil).Once()
+
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	result, err := service.GetMostFrequentBetween(context.Background(), since, until)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.StatsResponse{
+		Request:  request,
+		HitCount: 7,
+		Since:    &since,
+		Until:    &until,
+	}, result)
+}
+
+func TestStatsService_GetMostFrequentBetween_NoStats(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	since := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour)
+
+	mockRepo.EXPECT().GetMostFrequentBetween(mock.Anything, since, until).Return(nil, nil).Once()
+
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	result, err := service.GetMostFrequentBetween(context.Background(), since, until)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestStatsService_GetStoreMetrics(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+
+	expected := &model.StatsStoreMetrics{
+		Storage:        "memory",
+		Entries:        10000,
+		MaxEntries:     10000,
+		EvictionPolicy: "lru",
+		Evictions:      42,
+	}
+	mockRepo.EXPECT().GetStoreMetrics(mock.Anything).Return(expected, nil).Once()
+
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	result, err := service.GetStoreMetrics(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestStatsService_ExportStats(t *testing.T) {
+	mockRepo := mocks.NewMockIStatsRepository(t)
+	service := NewStatsService(mockRepo, newStatsServiceConfig())
+
+	entries := []model.StatsExportEntry{
+		{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}, HitCount: 42},
+	}
+	mockRepo.EXPECT().ExportEntries(mock.Anything).Return(entries, nil).Once()
+
+	export, err := service.ExportStats(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, entries, export.Entries)
+	assert.WithinDuration(t, time.Now(), export.ExportedAt, time.Minute)
+}
+
+func TestStatsService_ImportStats(t *testing.T) {
+	valid := model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}, HitCount: 1}
+
+	tests := []struct {
+		name          string
+		entry         model.StatsExportEntry
+		expectedError string // Empty: the entries reach the repository
+	}{
+		{name: "legacy_request", entry: valid},
+		{name: "rules_request", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}, HitCount: 3}},
+		{name: "no_hits", entry: model.StatsExportEntry{Request: valid.Request}, expectedError: "hit_count"},
+		{name: "no_limit", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Str1: "fizz", Str2: "buzz"}, HitCount: 1}, expectedError: "limit"},
+		{name: "missing_divisor", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Limit: 100, Str1: "fizz", Str2: "buzz"}, HitCount: 1}, expectedError: "divisors"},
+		{name: "empty_word", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2}}}, HitCount: 1}, expectedError: "words"},
+		{name: "divisor_above_max", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 1001, Word: "big"}}}, HitCount: 1}, expectedError: "between 1 and 1000"},
+		{name: "word_too_long", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "abcdefghijk"}}}, HitCount: 1}, expectedError: "between 1 and 10 characters"},
+		{name: "word_length_in_characters", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "ééééééé"}}}, HitCount: 3}},
+		{name: "word_with_nul", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "ev\x00en"}}}, HitCount: 1}, expectedError: "NUL"},
+		{name: "word_invalid_utf8", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 10, Str1: "fi\xffzz", Str2: "buzz"}, HitCount: 1}, expectedError: "UTF-8"},
+		{name: "rules_and_legacy", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Limit: 10, Rules: []model.Rule{{Divisor: 2, Word: "even"}}}, HitCount: 1}, expectedError: "combined"},
+		{name: "windowed", entry: model.StatsExportEntry{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz", Start: 5}, HitCount: 1}, expectedError: "start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockIStatsRepository(t)
+			service := NewStatsService(mockRepo, newStatsServiceConfig())
+			entries := []model.StatsExportEntry{valid, tt.entry}
+
+			if tt.expectedError == "" {
+				expected := &model.StatsImportResult{Entries: 2, Hits: 2}
+				mockRepo.EXPECT().ImportEntries(mock.Anything, entries).Return(expected, nil).Once()
+
+				result, err := service.ImportStats(context.Background(), entries)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, result)
+				return
+			}
+
+			// Nothing is imported when an entry is invalid
+			result, err := service.ImportStats(context.Background(), entries)
+			assert.Nil(t, result)
+			var importErr *StatsImportError
+			if assert.ErrorAs(t, err, &importErr) {
+				assert.Equal(t, 2, importErr.Entry)
+				assert.Contains(t, importErr.Reason, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+// Formats of stats exports and imports
+const (
+	StatsTransferJSON = "json" // A model.StatsExport document
+	StatsTransferCSV  = "csv"  // One row per entry, with the columns of statsCSVHeader
+)
+
+// statsCSVHeader lists the CSV columns: the parameter set as stored (rules is JSON-encoded and only
+// set for requests that do not use int1/str1 + int2/str2), then the hit count and request times.
+var statsCSVHeader = []string{"int1", "int2", "limit", "str1", "str2", "rules", "hit_count", "created_at", "updated_at"}
+
+// StatsImportError reports why an import was rejected. Nothing is imported when it is returned.
+type StatsImportError struct {
+	Entry  int // Position of the invalid entry, from 1; 0 when the document itself is malformed
+	Reason string
+}
+
+func (e *StatsImportError) Error() string {
+	if e.Entry == 0 {
+		return "invalid stats import: " + e.Reason
+	}
+	return fmt.Sprintf("invalid stats import: entry %d: %s", e.Entry, e.Reason)
+}
+
+// EncodeStatsExport writes export to w in format (StatsTransferJSON or StatsTransferCSV)
+func EncodeStatsExport(w io.Writer, format string, export *model.StatsExport) error {
+	switch format {
+	case StatsTransferJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+
+	case StatsTransferCSV:
+		writer := csv.NewWriter(w)
+		_ = writer.Write(statsCSVHeader)
+		for _, entry := range export.Entries {
+			record, err := statsCSVRecord(entry)
+			if err != nil {
+				return err
+			}
+			_ = writer.Write(record)
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		return fmt.Errorf("unsupported stats format %q: expected json or csv", format)
+	}
+}
+
+// DecodeStatsImport reads the entries of an export written by EncodeStatsExport.
+// Malformed documents are reported as a *StatsImportError.
+func DecodeStatsImport(r io.Reader, format string) ([]model.StatsExportEntry, error) {
+	switch format {
+	case StatsTransferJSON:
+		var export model.StatsExport
+		if err := json.NewDecoder(r).Decode(&export); err != nil {
+			return nil, &StatsImportError{Reason: err.Error()}
+		}
+		return export.Entries, nil
+
+	case StatsTransferCSV:
+		return decodeStatsCSV(r)
+
+	default:
+		return nil, fmt.Errorf("unsupported stats format %q: expected json or csv", format)
+	}
+}
+
+func statsCSVRecord(entry model.StatsExportEntry) ([]string, error) {
+	request := entry.Request
+	record := []string{"", "", strconv.Itoa(request.Limit), request.Str1, request.Str2, "",
+		strconv.FormatInt(entry.HitCount, 10), entry.CreatedAt.UTC().Format(time.RFC3339Nano), entry.UpdatedAt.UTC().Format(time.RFC3339Nano)}
+
+	if len(request.Rules) > 0 {
+		rules, err := json.Marshal(request.Rules)
+		if err != nil {
+			return nil, err
+		}
+		record[5] = string(rules)
+	} else {
+		record[0], record[1] = strconv.Itoa(request.Int1), strconv.Itoa(request.Int2)
+	}
+	return record, nil
+}
+
+// decodeStatsCSV maps columns by their header name, so columns may come in any order.
+// Empty int1/int2/rules and timestamps are allowed, limit and hit_count are required.
+func decodeStatsCSV(r io.Reader) ([]model.StatsExportEntry, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &StatsImportError{Reason: "missing CSV header: " + err.Error()}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, required := range []string{"limit", "hit_count"} {
+		if _, ok := columns[required]; !ok {
+			return nil, &StatsImportError{Reason: "missing CSV column " + required}
+		}
+	}
+
+	var entries []model.StatsExportEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		position := len(entries) + 1
+		if err != nil {
+			return nil, &StatsImportError{Entry: position, Reason: err.Error()}
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return record[i]
+			}
+			return ""
+		}
+		entry, reason := parseStatsCSVRecord(field)
+		if reason != "" {
+			return nil, &StatsImportError{Entry: position, Reason: reason}
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func parseStatsCSVRecord(field func(name string) string) (model.StatsExportEntry, string) {
+	var entry model.StatsExportEntry
+	var err error
+
+	for _, column := range []struct {
+		name   string
+		target *int
+	}{{"int1", &entry.Request.Int1}, {"int2", &entry.Request.Int2}, {"limit", &entry.Request.Limit}} {
+		if value := field(column.name); value != "" {
+			if *column.target, err = strconv.Atoi(value); err != nil {
+				return entry, column.name + " must be an integer"
+			}
+		}
+	}
+	entry.Request.Str1, entry.Request.Str2 = field("str1"), field("str2")
+
+	if rules := field("rules"); rules != "" {
+		if err := json.Unmarshal([]byte(rules), &entry.Request.Rules); err != nil {
+			return entry, "rules must be a JSON array of {divisor, word}"
+		}
+	}
+
+	if entry.HitCount, err = strconv.ParseInt(field("hit_count"), 10, 64); err != nil {
+		return entry, "hit_count must be an integer"
+	}
+
+	for _, column := range []struct {
+		name   string
+		target *time.Time
+	}{{"created_at", &entry.CreatedAt}, {"updated_at", &entry.UpdatedAt}} {
+		if value := field(column.name); value != "" {
+			if *column.target, err = time.Parse(time.RFC3339Nano, value); err != nil {
+				return entry, column.name + " must be an RFC 3339 timestamp"
+			}
+		}
+	}
+	return entry, ""
+}
+
+// validateStatsImportEntry checks that entry describes a parameter set the API could have recorded
+func (s *statsService) validateStatsImportEntry(entry model.StatsExportEntry) string {
+	request := entry.Request
+	switch {
+	case entry.HitCount < 1:
+		return "hit_count must be 1 or more"
+	case request.Limit < 1:
+		return "limit must be 1 or more"
+	case request.IsWindowed():
+		return "start, end, page_size and cursor are not recorded in stats"
+	case len(request.Rules) > 0 && request.HasLegacyParams():
+		return "rules cannot be combined with int1, int2, str1 or str2"
+	}
+
+	for _, rule := range request.EffectiveRules() {
+		if rule.Divisor < s.minDivisor || (s.maxDivisor > 0 && rule.Divisor > s.maxDivisor) {
+			if s.maxDivisor > 0 {
+				return fmt.Sprintf("divisors (int1, int2, rules) must be between %d and %d", s.minDivisor, s.maxDivisor)
+			}
+			return fmt.Sprintf("divisors (int1, int2, rules) must be %d or more", s.minDivisor)
+		}
+		if !utf8.ValidString(rule.Word) || strings.ContainsRune(rule.Word, 0) {
+			return "words (str1, str2, rules) must be valid UTF-8 text without NUL"
+		}
+		if length := utf8.RuneCountInString(rule.Word); length < s.minWordLength || length > s.maxWordLength {
+			return fmt.Sprintf("words (str1, str2, rules) must be between %d and %d characters", s.minWordLength, s.maxWordLength)
+		}
+	}
+	return ""
+}
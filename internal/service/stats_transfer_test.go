@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/julietteengel/fizzbuzz-api/internal/model"
+)
+
+func TestStatsTransfer_RoundTrip(t *testing.T) {
+	export := &model.StatsExport{
+		ExportedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Entries: []model.StatsExportEntry{
+			{
+				Request:   model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"},
+				HitCount:  42,
+				CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2025, 2, 1, 0, 0, 0, 500, time.UTC),
+			},
+			{
+				Request:   model.FizzBuzzRequest{Limit: 30, Rules: []model.Rule{{Divisor: 3, Word: "a,b"}, {Divisor: 5, Word: `"quoted"`}, {Divisor: 7, Word: "c"}}},
+				HitCount:  7,
+				CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, format := range []string{StatsTransferJSON, StatsTransferCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, EncodeStatsExport(&buf, format, export))
+
+			entries, err := DecodeStatsImport(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, export.Entries, entries)
+		})
+	}
+}
+
+func TestStatsTransfer_CSVColumnsByName(t *testing.T) {
+	// Columns in another order, optional ones missing
+	document := "hit_count,limit,str2,str1,int2,int1\n3,100,buzz,fizz,5,3\n"
+
+	entries, err := DecodeStatsImport(strings.NewReader(document), StatsTransferCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, []model.StatsExportEntry{
+		{Request: model.FizzBuzzRequest{Int1: 3, Int2: 5, Limit: 100, Str1: "fizz", Str2: "buzz"}, HitCount: 3},
+	}, entries)
+}
+
+func TestStatsTransfer_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		document      string
+		expectedEntry int
+		expectedError string
+	}{
+		{name: "invalid_json", format: StatsTransferJSON, document: `{"entries": [`, expectedError: "EOF"},
+		{name: "empty_csv", format: StatsTransferCSV, document: "", expectedError: "header"},
+		{name: "missing_column", format: StatsTransferCSV, document: "int1,int2,str1,str2,hit_count\n", expectedError: "limit"},
+		{name: "invalid_integer", format: StatsTransferCSV, document: "limit,hit_count\n10,1\nten,1\n", expectedEntry: 2, expectedError: "limit must be an integer"},
+		{name: "invalid_rules", format: StatsTransferCSV, document: "limit,rules,hit_count\n10,[,1\n", expectedEntry: 1, expectedError: "rules"},
+		{name: "invalid_timestamp", format: StatsTransferCSV, document: "limit,hit_count,created_at\n10,1,yesterday\n", expectedEntry: 1, expectedError: "created_at"},
+		{name: "wrong_field_count", format: StatsTransferCSV, document: "limit,hit_count\n10,1,extra\n", expectedEntry: 1, expectedError: "fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := DecodeStatsImport(strings.NewReader(tt.document), tt.format)
+
+			assert.Nil(t, entries)
+			var importErr *StatsImportError
+			if assert.ErrorAs(t, err, &importErr) {
+				assert.Equal(t, tt.expectedEntry, importErr.Entry)
+				assert.Contains(t, importErr.Reason, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestStatsTransfer_UnsupportedFormat(t *testing.T) {
+	assert.Error(t, EncodeStatsExport(&bytes.Buffer{}, "xml", &model.StatsExport{}))
+
+	_, err := DecodeStatsImport(strings.NewReader(""), "xml")
+	assert.Error(t, err)
+}